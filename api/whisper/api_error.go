@@ -0,0 +1,223 @@
+package whisper
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxErrorBody caps how much of an error response body is read.
+const maxErrorBody = 8 << 10
+
+// APIError is returned when the API responds with a non-200 status. The
+// fields other than StatusCode and Status are parsed from OpenAI's error
+// body; for other bodies Message holds the body text verbatim, or the text
+// of an HTML page. Error shortens long messages.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Message    string
+	Type       string
+	Code       string
+	Param      string
+	// RequestID is the x-request-id the server assigned to the request.
+	RequestID string
+	// Body is the decompressed response body, truncated to 8 KB.
+	Body []byte
+	// Elapsed is the time from sending the request until the response
+	// arrived.
+	Elapsed time.Duration
+}
+
+func (e *APIError) Error() string {
+	s := "unexpected response: " + e.Status
+	if msg := strings.Join(strings.Fields(e.Message), " "); msg != "" {
+		s += ": " + truncateMessage(redactSecrets(msg))
+	}
+	if e.RequestID != "" {
+		s += " (request ID " + e.RequestID + ")"
+	}
+	return s
+}
+
+// Is makes errors.Is(err, ErrUnauthorized) true for 401 responses.
+func (e *APIError) Is(target error) bool {
+	return target == ErrUnauthorized && e.StatusCode == http.StatusUnauthorized
+}
+
+// retryable reports whether the request may succeed if sent again.
+func (e *APIError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// RateLimitError is returned for 429 responses. It wraps the APIError, so
+// errors.As with an *APIError matches it as well.
+type RateLimitError struct {
+	*APIError
+	retryAfter time.Duration
+}
+
+// RetryAfter returns how long the server asked to wait before sending the
+// request again, taken from the Retry-After header or, if that is missing,
+// the x-ratelimit-reset-* headers. It is zero if the response had neither.
+func (e *RateLimitError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.APIError
+}
+
+// newRateLimitError builds a RateLimitError from the headers of a 429 response.
+func newRateLimitError(apiErr *APIError, h http.Header) *RateLimitError {
+	e := &RateLimitError{APIError: apiErr}
+	if v := strings.TrimSpace(h.Get("Retry-After")); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			e.retryAfter = time.Duration(secs) * time.Second
+		} else if t, err := http.ParseTime(v); err == nil {
+			e.retryAfter = time.Until(t)
+		}
+		if e.retryAfter < 0 {
+			e.retryAfter = 0
+		}
+		return e
+	}
+	if rl := parseRateLimit(h); rl != nil {
+		e.retryAfter = max(rl.ResetRequests, rl.ResetTokens)
+	}
+	return e
+}
+
+// responseError returns the error for a non-200 response whose (decoded)
+// body is r and that arrived after elapsed: a *RateLimitError for 429
+// responses, an *APIError otherwise.
+func responseError(resp *http.Response, r io.Reader, elapsed time.Duration) error {
+	apiErr := newAPIError(resp, r)
+	apiErr.Elapsed = elapsed
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return newRateLimitError(apiErr, resp.Header)
+	}
+	return apiErr
+}
+
+// maxDecodeSnippet caps how much of a response body a DecodeError keeps.
+const maxDecodeSnippet = 1 << 10
+
+// DecodeError is returned when a 200 response cannot be decoded.
+type DecodeError struct {
+	ContentType string
+	// Snippet is the start of the (decompressed) body, up to 1 KB.
+	Snippet []byte
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decoding response (Content-Type %q): %v; body: %q", e.ContentType, e.Err, e.Snippet)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// prefixWriter keeps the first max bytes written to it and discards the rest.
+type prefixWriter struct {
+	buf []byte
+	max int
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	if n := min(len(p), w.max-len(w.buf)); n > 0 {
+		w.buf = append(w.buf, p[:n]...)
+	}
+	return len(p), nil
+}
+
+// errorEnvelope is the JSON body of an OpenAI error response.
+type errorEnvelope struct {
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    any    `json:"code"`
+		Param   string `json:"param"`
+	} `json:"error"`
+}
+
+// newAPIError builds an APIError from a non-200 response whose (decoded) body
+// is r. Only JSON bodies are parsed as an OpenAI error envelope; other bodies,
+// such as the error pages of proxies, become a plain-text message.
+func newAPIError(resp *http.Response, r io.Reader) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  resp.Header.Get("x-request-id"),
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(r, maxErrorBody))
+	e.Body = body
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	// Some servers label JSON errors as text/plain, so valid JSON is parsed
+	// regardless of the declared type.
+	isJSON := mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || json.Valid(body)
+	if isJSON {
+		var env errorEnvelope
+		if err := json.Unmarshal(body, &env); err == nil && env.Error != nil && env.Error.Message != "" {
+			e.Message = env.Error.Message
+			e.Type = env.Error.Type
+			e.Param = env.Error.Param
+			if env.Error.Code != nil {
+				e.Code = fmt.Sprint(env.Error.Code)
+			}
+			return e
+		}
+	}
+	e.Message = string(body)
+	if mediaType == "text/html" || (mediaType == "" && looksLikeHTML(body)) {
+		e.Message = strings.Join(strings.Fields(htmlText(e.Message)), " ")
+	}
+	return e
+}
+
+// maxErrorMessage caps the length of the message included in APIError.Error.
+const maxErrorMessage = 300
+
+var (
+	htmlHiddenRe = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	htmlTitleRe  = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	htmlTagRe    = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// looksLikeHTML reports whether body appears to be an HTML document.
+func looksLikeHTML(body []byte) bool {
+	head := strings.ToLower(strings.TrimSpace(string(body[:min(len(body), 64)])))
+	return strings.HasPrefix(head, "<!doctype html") || strings.HasPrefix(head, "<html")
+}
+
+// htmlText renders an HTML page as plain text, leading with its title.
+func htmlText(page string) string {
+	text := htmlHiddenRe.ReplaceAllString(page, " ")
+	text = htmlTagRe.ReplaceAllString(text, " ")
+	if m := htmlTitleRe.FindStringSubmatch(page); m != nil {
+		text = m[1] + ": " + text
+	}
+	return html.UnescapeString(text)
+}
+
+// truncateMessage shortens msg to maxErrorMessage bytes at a rune boundary.
+func truncateMessage(msg string) string {
+	if len(msg) <= maxErrorMessage {
+		return msg
+	}
+	cut := maxErrorMessage
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut] + "..."
+}
@@ -0,0 +1,234 @@
+package whisper_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// transcribeError transcribes a second of audio against a server answering
+// with resp and returns the resulting *APIError.
+func transcribeError(t *testing.T, resp whispertest.Response) *whisper.APIError {
+	t.Helper()
+	srv := whispertest.NewServer(resp)
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var apiErr *whisper.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Transcribe() = %v, want an *APIError", err)
+	}
+	return apiErr
+}
+
+func TestAPIErrorBodies(t *testing.T) {
+	text := func(status int, contentType, body string) whispertest.Response {
+		return whispertest.Response{Status: status, Header: http.Header{"Content-Type": {contentType}}, Body: body}
+	}
+	tests := []struct {
+		name      string
+		resp      whispertest.Response
+		message   string
+		errorText string
+	}{
+		{
+			name:      "JSON envelope",
+			resp:      whispertest.Error(http.StatusBadRequest, "Invalid file format."),
+			message:   "Invalid file format.",
+			errorText: "unexpected response: 400 Bad Request: Invalid file format.",
+		},
+		{
+			name:      "plain text",
+			resp:      text(http.StatusBadGateway, "text/plain", "upstream timeout\n"),
+			message:   "upstream timeout\n",
+			errorText: "unexpected response: 502 Bad Gateway: upstream timeout",
+		},
+		{
+			name:      "plain text 403",
+			resp:      text(http.StatusForbidden, "text/plain; charset=utf-8", "Forbidden:\n  IP not allowed"),
+			message:   "Forbidden:\n  IP not allowed",
+			errorText: "unexpected response: 403 Forbidden: Forbidden: IP not allowed",
+		},
+		{
+			name: "HTML 502",
+			resp: text(http.StatusBadGateway, "text/html",
+				"<html><head><title>502 Bad Gateway</title><style>h1{}</style></head><body><h1>Bad&nbsp;Gateway</h1><p>nginx</p></body></html>"),
+			message:   "502 Bad Gateway: Bad Gateway nginx",
+			errorText: "unexpected response: 502 Bad Gateway: 502 Bad Gateway: Bad Gateway nginx",
+		},
+		{
+			name:      "empty 503",
+			resp:      whispertest.Response{Status: http.StatusServiceUnavailable},
+			message:   "",
+			errorText: "unexpected response: 503 Service Unavailable",
+		},
+		{
+			name:      "JSON labeled as text",
+			resp:      text(http.StatusBadRequest, "text/plain", `{"error":{"message":"bad model","type":"invalid_request_error","code":"model_not_found"}}`),
+			message:   "bad model",
+			errorText: "unexpected response: 400 Bad Request: bad model",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := transcribeError(t, tt.resp)
+			if apiErr.Message != tt.message {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.message)
+			}
+			if got := apiErr.Error(); got != tt.errorText {
+				t.Errorf("Error() = %q, want %q", got, tt.errorText)
+			}
+		})
+	}
+}
+
+func TestAPIErrorLongMessage(t *testing.T) {
+	body := strings.Repeat("upstream failure ", 100)
+	apiErr := transcribeError(t, whispertest.Response{Status: http.StatusBadGateway, Header: http.Header{"Content-Type": {"text/plain"}}, Body: body})
+	if apiErr.Message != body {
+		t.Errorf("Message was modified: %q", apiErr.Message)
+	}
+	if got := apiErr.Error(); len(got) > 400 || !strings.HasSuffix(got, "...") {
+		t.Errorf("Error() = %q, want it truncated", got)
+	}
+	if string(apiErr.Body) != body {
+		t.Errorf("Body = %q, want the response body", apiErr.Body)
+	}
+}
+
+func TestAPIErrorFields(t *testing.T) {
+	resp := whispertest.Response{
+		Status: http.StatusBadRequest,
+		Header: http.Header{"Content-Type": {"application/json"}, "X-Request-Id": {"req_123"}},
+		Body:   `{"error":{"message":"Unsupported language","type":"invalid_request_error","param":"language","code":400}}`,
+	}
+	apiErr := transcribeError(t, resp)
+	want := whisper.APIError{StatusCode: 400, Message: "Unsupported language", Type: "invalid_request_error", Code: "400", Param: "language", RequestID: "req_123"}
+	if apiErr.StatusCode != want.StatusCode || apiErr.Message != want.Message || apiErr.Type != want.Type ||
+		apiErr.Code != want.Code || apiErr.Param != want.Param || apiErr.RequestID != want.RequestID {
+		t.Errorf("APIError = %+v, want %+v", apiErr, want)
+	}
+	if !strings.HasSuffix(apiErr.Error(), "(request ID req_123)") {
+		t.Errorf("Error() = %q, want the request ID", apiErr.Error())
+	}
+}
+
+func TestAPIErrorUnauthorized(t *testing.T) {
+	apiErr := transcribeError(t, whispertest.Error(http.StatusUnauthorized, "Incorrect API key provided: sk-abcdefghijklmnopqrstuvwx."))
+	if !errors.Is(apiErr, whisper.ErrUnauthorized) {
+		t.Error("errors.Is(err, ErrUnauthorized) = false for a 401")
+	}
+	if strings.Contains(apiErr.Error(), "sk-abcdefghijklmnopqrstuvwx") {
+		t.Errorf("Error() = %q, leaks the key", apiErr.Error())
+	}
+}
+
+func TestRateLimitErrorRetryAfter(t *testing.T) {
+	srv := whispertest.NewServer(whispertest.RateLimited(7))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var rlErr *whisper.RateLimitError
+	if !errors.As(err, &rlErr) {
+		t.Fatalf("Transcribe() = %v, want a *RateLimitError", err)
+	}
+	if rlErr.RetryAfter() != 7*time.Second {
+		t.Errorf("RetryAfter() = %s, want 7s", rlErr.RetryAfter())
+	}
+	var apiErr *whisper.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("errors.As(err, *APIError) = %v, want the 429", apiErr)
+	}
+}
+
+func TestErrorElapsed(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		time.Sleep(delay)
+		http.Error(w, "upstream timed out", http.StatusGatewayTimeout)
+	}))
+	defer slow.Close()
+
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(slow.URL))
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var apiErr *whisper.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Transcribe() = %v, want an *APIError", err)
+	}
+	if apiErr.Elapsed < delay {
+		t.Errorf("Elapsed of a slow failure = %v, want at least %v", apiErr.Elapsed, delay)
+	}
+
+	fast := transcribeError(t, whispertest.Error(http.StatusBadRequest, "Invalid file format."))
+	if fast.Elapsed <= 0 || fast.Elapsed >= delay {
+		t.Errorf("Elapsed of a fast 400 = %v, want more than zero and less than %v", fast.Elapsed, delay)
+	}
+
+	c = whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(slow.URL), whisper.WithTimeout(delay/2))
+	_, err = c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var tErr *whisper.TransportError
+	if !errors.As(err, &tErr) {
+		t.Fatalf("Transcribe() with a timeout = %v, want a *TransportError", err)
+	}
+	if tErr.Elapsed < delay/2 {
+		t.Errorf("Elapsed of a timed out request = %v, want at least %v", tErr.Elapsed, delay/2)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	long := `{"text":"` + strings.Repeat("a", 5000) + `","segments":5}`
+	tests := []struct {
+		name, body string
+		snippet    string
+	}{
+		{"short", `{"text":"hi","segments":{"start":0}}`, `{"text":"hi","segments":{"start":0}}`},
+		{"long", long, long[:1024]},
+	}
+	for _, tt := range tests {
+		for _, compressed := range []bool{false, true} {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				if !compressed {
+					io.WriteString(w, tt.body)
+					return
+				}
+				w.Header().Set("Content-Encoding", "gzip")
+				zw := gzip.NewWriter(w)
+				io.WriteString(zw, tt.body)
+				zw.Close()
+			}))
+			c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+			_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+			srv.Close()
+
+			var decErr *whisper.DecodeError
+			if !errors.As(err, &decErr) {
+				t.Errorf("%s, gzip %v: Transcribe() = %v, want a *DecodeError", tt.name, compressed, err)
+				continue
+			}
+			if decErr.ContentType != "application/json; charset=utf-8" {
+				t.Errorf("%s, gzip %v: ContentType = %q", tt.name, compressed, decErr.ContentType)
+			}
+			if string(decErr.Snippet) != tt.snippet {
+				t.Errorf("%s, gzip %v: Snippet = %q (%d bytes), want the first %d bytes of the body",
+					tt.name, compressed, decErr.Snippet, len(decErr.Snippet), len(tt.snippet))
+			}
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				t.Errorf("%s, gzip %v: Transcribe() = %v, want it to wrap the *json.UnmarshalTypeError", tt.name, compressed, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package whisper
+
+import (
+	"net/url"
+	"strings"
+)
+
+// WithAzure configures the Client for an Azure OpenAI resource. Requests go
+// to the given deployment with the api-version query parameter, and the key
+// is sent in the api-key header instead of as a bearer token.
+func WithAzure(endpoint, deployment, apiVersion string) ClientOption {
+	return func(c *Client) {
+		c.azureEndpoint = strings.TrimRight(endpoint, "/")
+		c.baseURL = c.azureEndpoint + "/openai/deployments/" + url.PathEscape(deployment)
+		WithQuery("api-version", apiVersion)(c)
+	}
+}
@@ -0,0 +1,80 @@
+package whisper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxBatchErrorsShown is the number of failures BatchError.Error lists.
+const maxBatchErrorsShown = 3
+
+// BatchItemError is the failure of one input of a batch.
+type BatchItemError struct {
+	// Index is the position of the input in the batch.
+	Index int
+	// Input identifies the input, e.g. its path.
+	Input string
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Input, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError reports the inputs of a batch that failed. errors.Is and
+// errors.As look into the individual failures.
+type BatchError struct {
+	// Total is the number of inputs in the batch.
+	Total int
+	// Errors are the failures in input order.
+	Errors []*BatchItemError
+}
+
+func (e *BatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d inputs failed", len(e.Errors), e.Total)
+	for i, item := range e.Errors {
+		if i == maxBatchErrorsShown {
+			fmt.Fprintf(&b, "; and %d more", len(e.Errors)-i)
+			break
+		}
+		b.WriteString("; ")
+		b.WriteString(item.Error())
+	}
+	return b.String()
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, item := range e.Errors {
+		errs[i] = item
+	}
+	return errs
+}
+
+// Failed returns the indexes of the inputs that failed.
+func (e *BatchError) Failed() []int {
+	failed := make([]int, len(e.Errors))
+	for i, item := range e.Errors {
+		failed[i] = item.Index
+	}
+	return failed
+}
+
+// Succeeded returns the indexes of the inputs that succeeded.
+func (e *BatchError) Succeeded() []int {
+	var succeeded []int
+	next := 0
+	for i := 0; i < e.Total; i++ {
+		if next < len(e.Errors) && e.Errors[next].Index == i {
+			next++
+			continue
+		}
+		succeeded = append(succeeded, i)
+	}
+	return succeeded
+}
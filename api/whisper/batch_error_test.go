@@ -0,0 +1,75 @@
+package whisper_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+)
+
+func TestBatchError(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	good := write("good.wav", wavFile(1))
+	empty := write("empty.wav", nil)
+	missing := filepath.Join(dir, "missing.wav")
+	files := []string{good, empty, good, missing}
+
+	trs, err := c.TranscribeFiles(files)
+	var batchErr *whisper.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("TranscribeFiles() = %v, want a *BatchError", err)
+	}
+	if trs[0] == nil || trs[1] != nil || trs[2] == nil || trs[3] != nil {
+		t.Errorf("responses = %v, want responses for inputs 0 and 2 only", trs)
+	}
+	if got, want := batchErr.Failed(), []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Failed() = %v, want %v", got, want)
+	}
+	if got, want := batchErr.Succeeded(), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Succeeded() = %v, want %v", got, want)
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "2 of 4 inputs failed; "+empty+": ") || !strings.Contains(msg, "; "+missing+": ") {
+		t.Errorf("Error() = %q, want the count and each failed input", msg)
+	}
+
+	if !errors.Is(err, whisper.ErrEmptyFile) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("errors.Is does not reach the item errors of %v", err)
+	}
+	var item *whisper.BatchItemError
+	if !errors.As(err, &item) || item.Index != 1 || item.Input != empty {
+		t.Errorf("errors.As found %+v, want the first failed item", item)
+	}
+}
+
+func TestBatchErrorTruncated(t *testing.T) {
+	err := &whisper.BatchError{Total: 6}
+	for i := 0; i < 5; i++ {
+		err.Errors = append(err.Errors, &whisper.BatchItemError{Index: i, Input: string(rune('a' + i)), Err: errors.New("failed")})
+	}
+	if got, want := err.Error(), "5 of 6 inputs failed; a: failed; b: failed; c: failed; and 2 more"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := err.Succeeded(), []int{5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Succeeded() = %v, want %v", got, want)
+	}
+	if got := (&whisper.BatchError{Total: 2}).Failed(); len(got) != 0 {
+		t.Errorf("Failed() without errors = %v, want none", got)
+	}
+}
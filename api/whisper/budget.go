@@ -0,0 +1,176 @@
+package whisper
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+)
+
+// DefaultPricePerMinute is the price of whisper-1 in USD per minute of audio,
+// used to estimate costs when cost tracking is not enabled.
+const DefaultPricePerMinute = models.Whisper1RatePerMinute
+
+// probeSize is the number of leading bytes of the audio inspected to estimate its duration.
+const probeSize = 64 << 10
+
+// ErrBudgetExceeded is returned when the estimated cost of a request exceeds
+// the limit set with WithMaxCostPerRequest.
+var ErrBudgetExceeded = errors.New("request budget exceeded")
+
+// BudgetExceededError reports the estimated cost of a rejected request.
+type BudgetExceededError struct {
+	Duration time.Duration
+	Estimate float64
+	Limit    float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%v: estimated $%.4f for %s exceeds $%.4f", ErrBudgetExceeded, e.Estimate, e.Duration, e.Limit)
+}
+
+func (e *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// BudgetFlag modifies the behavior of WithMaxCostPerRequest.
+type BudgetFlag int
+
+// FailClosed rejects requests whose audio duration cannot be determined
+// locally instead of letting them through.
+const FailClosed BudgetFlag = 1
+
+// budget is the pre-flight cost check configured on a Client.
+type budget struct {
+	maxCost    float64
+	failClosed bool
+}
+
+// WithMaxCostPerRequest rejects requests whose estimated cost exceeds usd
+// before uploading them. The duration is determined locally from WAV and MP3
+// headers, or with the estimator set by WithDurationEstimator.
+func WithMaxCostPerRequest(usd float64, flags ...BudgetFlag) ClientOption {
+	return func(c *Client) {
+		c.budget = &budget{maxCost: usd}
+		for _, f := range flags {
+			if f == FailClosed {
+				c.budget.failClosed = true
+			}
+		}
+	}
+}
+
+// WithDurationEstimator sets the function used to determine the duration of
+// audio for WithMaxCostPerRequest, WithCostTracking and EstimateCost, e.g. one that shells out
+// to ffprobe. It is given the first bytes of the audio. WAVDuration is an
+// estimator for WAV files.
+func WithDurationEstimator(fn func(io.Reader) (time.Duration, error)) ClientOption {
+	return func(c *Client) {
+		c.durationEstimator = fn
+	}
+}
+
+// probeAudio determines the duration of the audio in h locally for the
+// budget and cost tracking. It returns a reader that yields the complete
+// audio. The duration is zero if it cannot be determined, in which case derr
+// says why.
+func (c *Client) probeAudio(h io.Reader, filename string) (d time.Duration, r io.Reader, derr, err error) {
+	size := sizeOf(h)
+	head, h, err := peek(h, probeSize)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	d, derr = c.durationOf(filename, head, size)
+	var panicErr *CallbackPanicError
+	if errors.As(derr, &panicErr) {
+		return 0, nil, nil, derr
+	}
+	return d, h, derr, nil
+}
+
+// durationOf determines the duration of audio from its leading bytes head
+// with the configured estimator, or from its header.
+func (c *Client) durationOf(filename string, head []byte, size int64) (time.Duration, error) {
+	if c.durationEstimator != nil {
+		return c.estimateDuration(head)
+	}
+	return probeDuration(filename, head, size)
+}
+
+// checkBudget fails if the estimated cost of audio of duration d exceeds the
+// budget. derr is the reason d could not be determined, if any.
+func (c *Client) checkBudget(d time.Duration, derr error) error {
+	if derr != nil {
+		if c.budget.failClosed {
+			return fmt.Errorf("%w: %v", ErrBudgetExceeded, derr)
+		}
+		return nil
+	}
+
+	price := DefaultPricePerMinute
+	if c.usage != nil {
+		price = c.usage.pricePerMinute
+	}
+	if cost := d.Minutes() * price; cost > c.budget.maxCost {
+		return &BudgetExceededError{Duration: d, Estimate: cost, Limit: c.budget.maxCost}
+	}
+	return nil
+}
+
+// EstimateCost estimates the cost in USD of transcribing file at the given
+// rate per minute, e.g. to confirm a large transcription with the user.
+// Nothing is uploaded. The duration is determined like for
+// WithMaxCostPerRequest, and is rounded to the second like the API bills it.
+func (c *Client) EstimateCost(file string, ratePerMinute float64) (float64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	size := sizeOf(f)
+	head, _, err := peek(f, probeSize)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := c.durationOf(file, head, size)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", file, err)
+	}
+	return d.Round(time.Second).Minutes() * ratePerMinute, nil
+}
+
+// estimateDuration calls the configured duration estimator on head.
+func (c *Client) estimateDuration(head []byte) (d time.Duration, err error) {
+	defer recoverCallback("duration estimator", &err)
+	return c.durationEstimator(bytes.NewReader(head))
+}
+
+// peek returns up to n leading bytes of h and a reader that still yields all
+// of h. Seekable readers are rewound so they remain seekable.
+func peek(h io.Reader, n int) ([]byte, io.Reader, error) {
+	if s, ok := h.(io.ReadSeeker); ok {
+		if off, err := s.Seek(0, io.SeekCurrent); err == nil {
+			head := make([]byte, n)
+			m, err := io.ReadFull(s, head)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				return nil, nil, err
+			}
+			if _, err := s.Seek(off, io.SeekStart); err != nil {
+				return nil, nil, err
+			}
+			return head[:m], s, nil
+		}
+	}
+	br := bufio.NewReaderSize(h, n)
+	head, err := br.Peek(n)
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	return head, br, nil
+}
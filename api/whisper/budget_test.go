@@ -0,0 +1,129 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestMaxCostPerRequest(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	ogg := audioOf("ogg")
+
+	tests := []struct {
+		name    string
+		opts    []whisper.ClientOption
+		audio   []byte
+		file    string
+		blocked bool
+	}{
+		{"within budget", []whisper.ClientOption{whisper.WithMaxCostPerRequest(0.01)}, wavFile(60), "a.wav", false},
+		{"over budget", []whisper.ClientOption{whisper.WithMaxCostPerRequest(0.001)}, wavFile(60), "a.wav", true},
+		{"unknown duration", []whisper.ClientOption{whisper.WithMaxCostPerRequest(0.001)}, ogg, "a.ogg", false},
+		{"unknown duration fail closed", []whisper.ClientOption{whisper.WithMaxCostPerRequest(0.001, whisper.FailClosed)}, ogg, "a.ogg", true},
+		{"tracked price", []whisper.ClientOption{whisper.WithMaxCostPerRequest(0.01), whisper.WithCostTracking(0.06)}, wavFile(60), "a.wav", true},
+		{
+			"estimator",
+			[]whisper.ClientOption{
+				whisper.WithMaxCostPerRequest(0.001),
+				whisper.WithDurationEstimator(func(io.Reader) (time.Duration, error) { return time.Hour, nil }),
+			},
+			ogg, "a.ogg", true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(srv.Requests())
+			c := whisper.NewClient(append([]whisper.ClientOption{whisper.WithKey("k"), whisper.WithBaseURL(srv.URL)}, tt.opts...)...)
+			_, err := c.Transcribe(bytes.NewReader(tt.audio), transcribe.WithFile(tt.file))
+			if tt.blocked != errors.Is(err, whisper.ErrBudgetExceeded) {
+				t.Errorf("Transcribe() = %v, want blocked %v", err, tt.blocked)
+			}
+			if sent := len(srv.Requests()) > before; sent == tt.blocked {
+				t.Errorf("request sent = %v, want %v", sent, !tt.blocked)
+			}
+		})
+	}
+}
+
+func TestBudgetExceededError(t *testing.T) {
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL("http://127.0.0.1:0"), whisper.WithMaxCostPerRequest(0.001))
+	_, err := c.Transcribe(bytes.NewReader(wavFile(60)), transcribe.WithFile("a.wav"))
+	var budgetErr *whisper.BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("Transcribe() = %v, want a *BudgetExceededError", err)
+	}
+	if budgetErr.Duration != time.Minute || math.Abs(budgetErr.Estimate-0.006) > 1e-9 || budgetErr.Limit != 0.001 {
+		t.Errorf("BudgetExceededError = %+v", budgetErr)
+	}
+}
+
+func TestDurationEstimatorPanic(t *testing.T) {
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL("http://127.0.0.1:0"),
+		whisper.WithMaxCostPerRequest(1),
+		whisper.WithDurationEstimator(func(io.Reader) (time.Duration, error) { panic("boom") }))
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var panicErr *whisper.CallbackPanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Transcribe() = %v, want a *CallbackPanicError", err)
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	dir := t.TempDir()
+	wav := filepath.Join(dir, "a.wav")
+	if err := os.WriteFile(wav, wavFile(90), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := whisper.NewClient(whisper.WithKey("k"))
+	cost, err := c.EstimateCost(wav, 0.006)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(cost-0.009) > 1e-9 {
+		t.Errorf("EstimateCost() = %v, want 0.009", cost)
+	}
+
+	// Streamed WAVs leave the data chunk size unset, so the duration comes
+	// from the file size.
+	streamed := wavFile(60)
+	copy(streamed[40:44], []byte{0, 0, 0, 0})
+	wav = filepath.Join(dir, "streamed.wav")
+	if err := os.WriteFile(wav, streamed, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if cost, err = c.EstimateCost(wav, 0.006); err != nil || math.Abs(cost-0.006) > 1e-9 {
+		t.Errorf("EstimateCost() of a streamed WAV = %v, %v, want 0.006", cost, err)
+	}
+
+	ogg := filepath.Join(dir, "a.ogg")
+	if err := os.WriteFile(ogg, audioOf("ogg"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.EstimateCost(ogg, 0.006); err == nil {
+		t.Error("EstimateCost of an Ogg file succeeded, want an unknown duration error")
+	}
+}
+
+func TestWAVDuration(t *testing.T) {
+	d, err := whisper.WAVDuration(bytes.NewReader(wavFile(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 3*time.Second {
+		t.Errorf("WAVDuration() = %s, want 3s", d)
+	}
+	if _, err := whisper.WAVDuration(bytes.NewReader(audioOf("mp3"))); err == nil {
+		t.Error("WAVDuration of an MP3 succeeded")
+	}
+}
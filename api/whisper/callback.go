@@ -0,0 +1,59 @@
+package whisper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// errNotInitialized is returned by a Client that was not created with New or
+// NewClient, e.g. a zero Client{}.
+var errNotInitialized = errors.New("client not initialized; create it with New or NewClient")
+
+// CallbackPanicError is returned when a user-supplied function, such as a
+// retry callback, a duration estimator, a credentials provider or an HTTP
+// middleware, panics. The panic is recovered so the caller does not crash.
+type CallbackPanicError struct {
+	// Callback names the function that panicked.
+	Callback string
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *CallbackPanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %v", e.Callback, e.Value)
+}
+
+// recoverCallback turns a panic in the named callback into a
+// *CallbackPanicError stored in *err. It must be deferred.
+func recoverCallback(name string, err *error) {
+	if v := recover(); v != nil {
+		*err = &CallbackPanicError{Callback: name, Value: v, Stack: debug.Stack()}
+	}
+}
+
+// ready returns the error that keeps the Client from sending requests, if any.
+func (c *Client) ready() error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.httpClient == nil || c.credentials == nil || c.rateLimit == nil {
+		return errNotInitialized
+	}
+	return nil
+}
+
+// do sends req with the HTTP client. Panics of middlewares and custom
+// transports are returned as errors, and a missing response body is
+// replaced with an empty one.
+func (c *Client) do(req *http.Request) (resp *http.Response, err error) {
+	defer recoverCallback("HTTP transport", &err)
+	resp, err = c.httpClient.Do(req)
+	if resp != nil && resp.Body == nil {
+		resp.Body = http.NoBody
+	}
+	return resp, err
+}
@@ -0,0 +1,96 @@
+package whisper_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+type panickingCredentials struct{}
+
+func (panickingCredentials) APIKey(context.Context) (string, error) {
+	panic("vault unreachable")
+}
+
+func TestCallbackPanics(t *testing.T) {
+	panicking := func(http.RoundTripper) http.RoundTripper {
+		return whisper.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+			panic("middleware bug")
+		})
+	}
+	tests := []struct {
+		name     string
+		client   []whisper.ClientOption
+		opts     []transcribe.TranscribeOption
+		callback string
+	}{
+		{
+			name:     "middleware",
+			client:   []whisper.ClientOption{whisper.WithRoundTripper(panicking)},
+			callback: "HTTP transport",
+		},
+		{
+			name:     "credentials provider",
+			client:   []whisper.ClientOption{whisper.WithCredentialsProvider(panickingCredentials{})},
+			callback: "credentials provider",
+		},
+		{
+			name:   "retry callback",
+			client: []whisper.ClientOption{whisper.WithMaxRetries(1)},
+			opts: []transcribe.TranscribeOption{transcribe.WithOnRetry(func(int, error, time.Duration) {
+				panic("retry hook bug")
+			})},
+			callback: "retry callback",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := whispertest.NewServer(rateLimited(), jsonText("hello"))
+			defer srv.Close()
+			c := whisper.NewClient(append([]whisper.ClientOption{whisper.WithKey("k"), whisper.WithBaseURL(srv.URL)}, tt.client...)...)
+
+			opts := append([]transcribe.TranscribeOption{transcribe.WithFile("a.wav")}, tt.opts...)
+			_, err := c.Transcribe(bytes.NewReader(wavFile(1)), opts...)
+			var panicErr *whisper.CallbackPanicError
+			if !errors.As(err, &panicErr) {
+				t.Fatalf("Transcribe() = %v, want a *CallbackPanicError", err)
+			}
+			if panicErr.Callback != tt.callback || len(panicErr.Stack) == 0 {
+				t.Errorf("CallbackPanicError{Callback: %q, %d bytes of stack}, want %q and a stack", panicErr.Callback, len(panicErr.Stack), tt.callback)
+			}
+		})
+	}
+}
+
+func TestNilResponseBody(t *testing.T) {
+	noBody := func(http.RoundTripper) http.RoundTripper {
+		return whisper.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}, Request: req}, nil
+		})
+	}
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL("http://whisper.invalid"), whisper.WithRoundTripper(noBody))
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var apiErr *whisper.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Transcribe() = %v, want a 502 *APIError", err)
+	}
+}
+
+func TestZeroClient(t *testing.T) {
+	var c whisper.Client
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err == nil || !strings.Contains(err.Error(), "NewClient") {
+		t.Errorf("Transcribe() on a zero Client = %v, want an error pointing to NewClient", err)
+	}
+	if err := c.Ping(context.Background()); err == nil {
+		t.Error("Ping() on a zero Client succeeded")
+	}
+}
@@ -0,0 +1,50 @@
+package whisper
+
+import (
+	"context"
+	"io"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// TranscribeHandle is a transcription running in the background, started
+// by TranscribeCancelable.
+type TranscribeHandle struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	tr     *models.TranscribeResponse
+	err    error
+}
+
+// TranscribeCancelable starts transcribing the audio read from h in the
+// background and returns a handle to cancel it or wait for its result,
+// e.g. for a stop button in a UI. h must not be used until Wait returns.
+// An error is only returned if the client cannot send requests.
+func (c *Client) TranscribeCancelable(h io.Reader, opts ...transcribe.TranscribeOption) (*TranscribeHandle, error) {
+	if err := c.ready(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &TranscribeHandle{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(t.done)
+		defer cancel()
+		t.tr, t.err = c.TranscribeContext(ctx, h, opts...)
+	}()
+	return t, nil
+}
+
+// Cancel stops the transcription. Wait then reports an error wrapping
+// context.Canceled, unless the transcription already completed. Cancel may
+// be called from any goroutine and more than once.
+func (t *TranscribeHandle) Cancel() {
+	t.cancel()
+}
+
+// Wait waits for the transcription to complete and returns its result. It
+// may be called more than once.
+func (t *TranscribeHandle) Wait() (*models.TranscribeResponse, error) {
+	<-t.done
+	return t.tr, t.err
+}
@@ -0,0 +1,84 @@
+package whisper_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestTranscribeCancelable(t *testing.T) {
+	received := make(chan struct{})
+	var once sync.Once
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		once.Do(func() { close(received) })
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	h, err := c.TranscribeCancelable(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-received
+	h.Cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tr, err := h.Wait()
+		if tr != nil || !errors.Is(err, context.Canceled) {
+			t.Errorf("Wait() = %v, %v; want context.Canceled", tr, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait() did not return after Cancel")
+	}
+	h.Cancel()
+	if _, err := h.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("second Wait() = %v, want context.Canceled", err)
+	}
+}
+
+func TestTranscribeCancelableCompleted(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	h, err := c.TranscribeCancelable(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := h.Wait()
+	if err != nil || tr.Text != "hello" {
+		t.Fatalf("Wait() = %v, %v; want the transcript", tr, err)
+	}
+	h.Cancel()
+	if tr, err := h.Wait(); err != nil || tr.Text != "hello" {
+		t.Errorf("Wait() after a late Cancel = %v, %v; want the transcript", tr, err)
+	}
+}
+
+func TestTranscribeCancelableZeroClient(t *testing.T) {
+	var c whisper.Client
+	if _, err := c.TranscribeCancelable(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err == nil {
+		t.Error("TranscribeCancelable() on a zero Client succeeded")
+	}
+}
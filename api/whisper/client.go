@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"compress/flate"
 	"compress/gzip"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/akhilsharma90/go-whisper-project/models"
 	"github.com/akhilsharma90/go-whisper-project/transcribe"
@@ -21,24 +28,72 @@ import (
 const (
 	DefaultBase  = "https://api.openai.com/v1"
 	DefaultModel = "whisper-1"
+
+	// DefaultResponseFormat is the response_format used when none is set.
+	DefaultResponseFormat = "json"
+
+	// DefaultMaxResponseSize is the default cap on the decompressed size of a response body.
+	DefaultMaxResponseSize int64 = 64 << 20
+
+	// DefaultMaxUploadSize is the largest audio file the OpenAI API accepts.
+	DefaultMaxUploadSize int64 = 25 << 20
+)
+
+// Paths of the audio endpoints relative to the base URL.
+const (
+	transcriptionsPath = "audio/transcriptions"
+	translationsPath   = "audio/translations"
 )
 
 // Client is the main structure for interacting with the Whisper ASR API.
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
+	credentials CredentialsProvider
+	baseURL     string
+	httpClient  *http.Client
+	// baseHTTPClient is httpClient before it is wrapped with the middlewares.
+	baseHTTPClient  *http.Client
+	transport       *http.Transport
+	tlsConfig       *tls.Config
+	tlsChanged      bool
+	middlewares     []func(http.RoundTripper) http.RoundTripper
+	maxResponseSize int64
+	maxUploadSize   int64
+	noCompression   bool
+	accept          string
+	boundary        string
+	autoDowngrade   bool
+	defaultModel    string
+	fileField       string
+	connectionStats bool
+	maxRetries      int
+	query           url.Values
+	headers         http.Header
+	timeout         time.Duration
+	azureEndpoint   string
+	envPrefix       string
+	fallbacks       []Endpoint
+	logger          *slog.Logger
+	usage           *usageTracker
+	rateLimit       *rateLimitState
+
+	// sensitiveHeaders are the headers marked with WithSensitiveHeader.
+	sensitiveHeaders []string
+
+	defaultOpts []transcribe.TranscribeOption
+
+	budget            *budget
+	durationEstimator func(io.Reader) (time.Duration, error)
+
+	// err is the first error reported by an option.
+	err error
 }
 
 // ClientOption is a function type that allows to set options for the Client.
 type ClientOption func(*Client)
 
-
 // WithKey sets the API key for the Client.
 func WithKey(key string) ClientOption {
-	return func(c *Client) {
-		c.apiKey = key
-	}
+	return WithCredentialsProvider(StaticCredentials(key))
 }
 
 // WithBaseURL sets the base URL for the Client.
@@ -48,6 +103,63 @@ func WithBaseURL(url string) ClientOption {
 	}
 }
 
+// WithQuery adds a query parameter that is sent with every request, e.g. the
+// api-version required by Azure-compatible proxies.
+func WithQuery(key, value string) ClientOption {
+	return func(c *Client) {
+		if c.query == nil {
+			c.query = url.Values{}
+		}
+		c.query.Add(key, value)
+	}
+}
+
+// WithHeaders adds headers that are sent with every request.
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		for k, v := range headers {
+			c.headers.Set(k, v)
+		}
+	}
+}
+
+// WithTimeout bounds the total time of a transcription, including retries.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithResponseCompression controls whether the Client requests compressed
+// responses and decompresses them itself (the default). When disabled, no
+// Accept-Encoding header is set and the transport's transparent gzip handling
+// applies instead.
+func WithResponseCompression(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.noCompression = !enabled
+	}
+}
+
+// WithDefaultOptions sets transcription options applied to every request
+// before the per-call options, which take precedence.
+func WithDefaultOptions(opts ...transcribe.TranscribeOption) ClientOption {
+	return func(c *Client) {
+		c.defaultOpts = append(c.defaultOpts, opts...)
+	}
+}
+
+// WithLogger sets a structured logger for request diagnostics. Requests are
+// logged at debug level and their outcome at info or error level. Nothing is
+// logged when no logger is set.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
 // WithHTTPClient sets the HTTP client for the Client.
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(c *Client) {
@@ -55,134 +167,746 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
-// NewClient creates a new Whisper ASR API client with the given options.
+// WithMaxResponseSize sets the maximum number of bytes read from a (decompressed) response body.
+func WithMaxResponseSize(bytes int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseSize = bytes
+	}
+}
+
+// WithDefaultModel sets the model used by requests that do not set one with
+// transcribe.WithModel, instead of DefaultModel.
+func WithDefaultModel(model string) ClientOption {
+	return func(c *Client) {
+		c.defaultModel = model
+	}
+}
+
+// WithAcceptHeader sets the Accept header of transcription requests. By
+// default it is application/json for the JSON response formats and */* for
+// the others.
+func WithAcceptHeader(accept string) ClientOption {
+	return func(c *Client) {
+		c.accept = accept
+	}
+}
+
+// WithMaxUploadSize sets the maximum size of the audio in bytes, for backends
+// that accept larger files than DefaultMaxUploadSize.
+func WithMaxUploadSize(bytes int64) ClientOption {
+	return func(c *Client) {
+		c.maxUploadSize = bytes
+	}
+}
+
+// New creates a new Whisper ASR API client with the given options and
+// reports any option that failed to apply, such as an unreadable certificate.
+func New(opts ...ClientOption) (*Client, error) {
+	c := NewClient(opts...)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c, nil
+}
+
+// NewClient creates a new Whisper ASR API client with the given options. An
+// option that fails to apply makes every request fail; use New to detect it
+// up front.
 func NewClient(opts ...ClientOption) *Client {
-	c := &Client{}
+	c := &Client{rateLimit: &rateLimitState{}}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	if c.apiKey == "" {
-		c.apiKey = os.Getenv("OPENAI_API_KEY")
+	if c.envPrefix == "" {
+		c.envPrefix = DefaultEnvPrefix
+	}
+	if c.credentials == nil || c.credentials == StaticCredentials("") {
+		c.credentials = StaticCredentials(os.Getenv(c.envPrefix + "API_KEY"))
 	}
 	if c.baseURL == "" {
-		c.baseURL = os.Getenv("OPENAI_BASE_URL")
+		c.baseURL = os.Getenv(c.envPrefix + "BASE_URL")
+	}
+	if c.tlsConfig != nil {
+		if err := c.applyTLS(); err != nil {
+			c.setErr(err)
+		}
 	}
 	if c.httpClient == nil {
-		c.httpClient = http.DefaultClient
+		if c.transport == nil {
+			c.transport = newTransport()
+		}
+		c.httpClient = &http.Client{Transport: c.transport}
+	}
+	c.baseHTTPClient = c.httpClient
+	c.httpClient = wrapTransport(c.httpClient, c.middlewares)
+	if c.maxResponseSize <= 0 {
+		c.maxResponseSize = DefaultMaxResponseSize
+	}
+	if c.fileField == "" {
+		c.fileField = DefaultFileFieldName
+	}
+	if c.maxUploadSize <= 0 {
+		c.maxUploadSize = DefaultMaxUploadSize
 	}
 
 	return c
 }
 
+// setErr records the first error reported by an option.
+func (c *Client) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// Err returns the first error reported by an option, if any.
+func (c *Client) Err() error {
+	return c.err
+}
+
+// Clone returns a copy of the Client with the given options applied on top of
+// its configuration. The clone shares the underlying HTTP client unless it is
+// replaced with WithHTTPClient or WithTransport, and tracks its own usage.
+// All middlewares, inherited and added, wrap the clone's HTTP client. TLS
+// options cannot be applied to a clone.
+func (c *Client) Clone(opts ...ClientOption) *Client {
+	clone := *c
+	clone.tlsConfig = c.tlsConfig.Clone()
+	clone.tlsChanged = false
+	if c.query != nil {
+		clone.query = make(url.Values, len(c.query))
+		for k, vs := range c.query {
+			clone.query[k] = append([]string(nil), vs...)
+		}
+	}
+	clone.headers = c.headers.Clone()
+	clone.sensitiveHeaders = slices.Clone(c.sensitiveHeaders)
+	clone.fallbacks = append([]Endpoint(nil), c.fallbacks...)
+	clone.rateLimit = &rateLimitState{}
+	clone.defaultOpts = append([]transcribe.TranscribeOption(nil), c.defaultOpts...)
+	if c.usage != nil {
+		clone.usage = &usageTracker{pricePerMinute: c.usage.pricePerMinute}
+	}
+	clone.middlewares = append([]func(http.RoundTripper) http.RoundTripper(nil), c.middlewares...)
+	clone.httpClient = c.baseHTTPClient
+	for _, opt := range opts {
+		opt(&clone)
+	}
+	if clone.tlsChanged {
+		clone.setErr(errors.New("TLS options cannot be applied to a cloned client"))
+	}
+	// A new transport replaces the one of the HTTP client the Client created.
+	created := c.baseHTTPClient != nil && c.baseHTTPClient.Transport == http.RoundTripper(c.transport)
+	if created && clone.httpClient == c.baseHTTPClient && clone.transport != c.transport {
+		clone.httpClient = &http.Client{Transport: clone.transport}
+	}
+	if clone.httpClient == c.baseHTTPClient && len(clone.middlewares) == len(c.middlewares) {
+		clone.httpClient = c.httpClient
+		return &clone
+	}
+	clone.baseHTTPClient = clone.httpClient
+	clone.httpClient = wrapTransport(clone.httpClient, clone.middlewares)
+	return &clone
+}
+
+// TranscribeBytes transcribes the audio in data. The filename must be set
+// with transcribe.WithFile.
+func (c *Client) TranscribeBytes(data []byte, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	return c.TranscribeBytesContext(context.Background(), data, opts...)
+}
+
+// TranscribeBytesContext is like TranscribeBytes but carries a context for the request.
+func (c *Client) TranscribeBytesContext(ctx context.Context, data []byte, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	return c.TranscribeContext(ctx, bytes.NewReader(data), opts...)
+}
+
+// TranscribeFile transcribes the audio file at the given path.
 func (c *Client) TranscribeFile(file string, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	return c.TranscribeFileContext(context.Background(), file, opts...)
+}
+
+// TranscribeFileContext is like TranscribeFile but carries a context for the
+// request. The file is closed as soon as ctx is done, which also aborts an
+// upload in progress. Directories and empty files are rejected before
+// anything is sent.
+func (c *Client) TranscribeFileContext(ctx context.Context, file string, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	fi, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return nil, fmt.Errorf("%s: is a directory", file)
+	}
+	if fi.Mode().IsRegular() && fi.Size() == 0 {
+		return nil, fmt.Errorf("%s: %w", file, ErrEmptyFile)
+	}
 	h, err := os.Open(file)
 	if err != nil {
 		return nil, err
 	}
 	defer h.Close()
+	stop := context.AfterFunc(ctx, func() { h.Close() })
+	defer stop()
 
 	opts = append([]transcribe.TranscribeOption{transcribe.WithFile(filepath.Base(file))}, opts...)
-	return c.Transcribe(h, opts...)
+	return c.TranscribeContext(ctx, h, opts...)
 }
 
-
-// URL constructs the full URL for the given relative path.
+// URL constructs the full URL for the given relative path, appending any
+// query parameters configured with WithQuery.
 func (c *Client) URL(relPath string) string {
-	if strings.Contains(relPath, "://") {
+	return c.urlFor(c.baseURL, relPath)
+}
+
+// urlFor constructs the full URL for relPath against baseURL, keeping the
+// path and query parameters of baseURL. The query parameters of the Client
+// are only added to URLs on the host of baseURL.
+func (c *Client) urlFor(baseURL, relPath string) string {
+	u, err := url.Parse(relPath)
+	if err != nil {
 		return relPath
 	}
-	baseURL := c.baseURL
 	if baseURL == "" {
 		baseURL = DefaultBase
 	}
-	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(relPath, "/")
+	base, baseErr := url.Parse(baseURL)
+	if !u.IsAbs() {
+		if baseErr != nil {
+			return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(relPath, "/")
+		}
+		// relPath is resolved below the base path: a base path without a
+		// trailing slash would lose its last segment, and a rooted relPath
+		// would replace it entirely.
+		if !strings.HasSuffix(base.Path, "/") {
+			base.Path += "/"
+			if base.RawPath != "" {
+				base.RawPath += "/"
+			}
+		}
+		rel := *u
+		rel.Path = strings.TrimLeft(rel.Path, "/")
+		rel.RawPath = ""
+		u = base.ResolveReference(&rel)
+		// Query parameters of the base, e.g. an api-version, are kept.
+		switch {
+		case base.RawQuery != "" && rel.RawQuery != "":
+			u.RawQuery = base.RawQuery + "&" + rel.RawQuery
+		case base.RawQuery != "":
+			u.RawQuery = base.RawQuery
+		}
+		u.Fragment = ""
+	} else if baseErr != nil || !strings.EqualFold(u.Host, base.Host) {
+		// The query parameters may hold an api-version or a key meant for
+		// the API only.
+		return u.String()
+	}
+	if len(c.query) > 0 {
+		q := u.Query()
+		for k, vs := range c.query {
+			for _, v := range vs {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
 }
 
-
 // Transcribe transcribes the given audio stream using the Whisper ASR API.
 func (c *Client) Transcribe(h io.Reader, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
-	if c.apiKey == "" {
-		return nil, errors.New("missing API key (set OPENAI_API_KEY in env)")
+	return c.TranscribeContext(context.Background(), h, opts...)
+}
+
+// TranscribeContext is like Transcribe but carries a context for the request.
+func (c *Client) TranscribeContext(ctx context.Context, h io.Reader, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	return c.call(ctx, transcriptionsPath, h, opts)
+}
+
+// call sends the audio read from h to the endpoint at path, which is
+// transcriptionsPath or translationsPath.
+func (c *Client) call(ctx context.Context, path string, h io.Reader, opts []transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	if err := c.ready(); err != nil {
+		return nil, err
 	}
 
 	tc := &transcribe.TranscribeConfig{}
+	for _, opt := range c.defaultOpts {
+		opt(tc)
+	}
 	for _, opt := range opts {
 		opt(tc)
 	}
-
-	if tc.Model == "" {
-		tc.Model = DefaultModel
+	if tc.RequestID == "" {
+		tc.RequestID = newUUID()
+	}
+	parent := ctx
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
 	}
 
-	if tc.File == "" {
-		return nil, errors.New("filename is not set")
+	st := callState{path: path}
+	tr, err := c.transcribe(ctx, h, tc, &st)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded && parent.Err() == nil && !errors.Is(err, ErrTimeout) {
+			err = fmt.Errorf("%w after %s: %w", ErrTimeout, c.timeout, err)
+		}
+		return nil, &RequestError{ID: tc.RequestID, Err: err, ServerRequestIDs: st.serverIDs}
 	}
+	tr.SourceFile = tc.File
+	tr.Meta.RequestID = tc.RequestID
+	tr.Meta.Attempts = st.attempts
+	if err := tr.ApplyReplacements(tc.Replacements); err != nil {
+		return nil, &RequestError{ID: tc.RequestID, Err: err, ServerRequestIDs: st.serverIDs}
+	}
+	if tc.NormalizeText {
+		tr.Text = tr.NormalizedText(tc.NormalizeOptions...)
+	}
+	if tc.TimestampOffset != 0 {
+		offsetTimestamps(tr, tc.TimestampOffset)
+	}
+	if c.usage != nil {
+		tr.Meta.Cost, tr.Meta.CostKnown = c.usage.record(tr, st.duration)
+	}
+	return tr, nil
+}
 
-	b := &bytes.Buffer{}
-	mp := multipart.NewWriter(b)
+// callState describes a call and collects information about its attempts.
+type callState struct {
+	// path is the endpoint path, transcriptionsPath or translationsPath.
+	path string
+	// size is the size of the audio in bytes, or -1 if unknown.
+	size int64
+	// duration is the duration of the audio determined locally, or zero if
+	// unknown or not needed.
+	duration time.Duration
+
+	attempts int
+	// serverIDs are the server request IDs of the failed attempts.
+	serverIDs []string
+}
 
-	f, err := mp.CreateFormField("model")
+// record counts an attempt that ended with err, which may be nil.
+func (st *callState) record(err error) {
+	st.attempts++
 	if err != nil {
-		return nil, err
+		st.serverIDs = appendServerRequestID(st.serverIDs, err)
 	}
-	f.Write([]byte(tc.Model))
+}
 
-	if f, err = mp.CreateFormField("response_format"); err != nil {
+// transcribe sends the audio to the endpoint of the call for the given
+// configuration, retrying failed attempts if the Client is configured to.
+// The attempts are recorded in st.
+func (c *Client) transcribe(ctx context.Context, h io.Reader, tc *transcribe.TranscribeConfig, st *callState) (*models.TranscribeResponse, error) {
+	if err := tc.Err(); err != nil {
 		return nil, err
 	}
-	f.Write([]byte("verbose_json"))
 
-	fp, err := mp.CreateFormFile("file", tc.File)
+	apiKey, err := c.resolveAPIKey(ctx, tc.APIKey)
 	if err != nil {
 		return nil, err
 	}
-	if _, err = io.Copy(fp, h); err != nil {
+
+	if tc.Model == "" {
+		tc.Model = c.defaultModel
+	}
+	if tc.Model == "" {
+		tc.Model = DefaultModel
+	}
+	if tc.ResponseFormat == "" {
+		tc.ResponseFormat = DefaultResponseFormat
+	}
+
+	if tc.File == "" {
+		return nil, ErrMissingFilename
+	}
+	if err := checkCompatibility(st.path, tc); err != nil {
 		return nil, err
 	}
-	mp.Close()
+	if tc.Language != "" {
+		if tc.Language, err = normalizeLanguage(tc.Language); err != nil {
+			return nil, err
+		}
+	}
 
-	url := c.URL("audio/transcriptions")
-	req, err := http.NewRequest(http.MethodPost, url, b)
+	size := sizeOf(h)
+	st.size = size
+	head, h, err := peek(h, sniffLen)
 	if err != nil {
 		return nil, err
 	}
+	if size == 0 || len(head) == 0 {
+		return nil, fmt.Errorf("%s: %w", tc.File, ErrEmptyFile)
+	}
+	if tc.File, err = checkFormat(tc.File, head); err != nil {
+		return nil, err
+	}
+	if size > c.maxUploadSize {
+		return nil, fmt.Errorf("%s: %w (%d bytes, limit %d)", tc.File, ErrFileTooLarge, size, c.maxUploadSize)
+	}
+	if c.budget != nil || c.usage != nil {
+		var derr error
+		if st.duration, h, derr, err = c.probeAudio(h, tc.File); err != nil {
+			return nil, err
+		}
+		if c.budget != nil {
+			if err := c.checkBudget(st.duration, derr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if tc.PromptTokenLimit > 0 {
+		tc.Prompt = truncatePrompt(tc.Prompt, tc.PromptTokenLimit)
+	}
+
+	if c.logger != nil {
+		c.logger.Debug("transcribe request", "request_id", tc.RequestID, "model", tc.Model, "file", tc.File, "bytes", size)
+	}
+
+	endpoints := []Endpoint{{BaseURL: c.baseURL, APIKey: apiKey}}
+	for _, ep := range c.fallbacks {
+		if ep.APIKey == "" {
+			ep.APIKey = apiKey
+		}
+		endpoints = append(endpoints, ep)
+	}
+	maxRetries := c.maxRetries
+	if tc.NoRetry {
+		maxRetries = 0
+	}
+	if maxRetries == 0 && len(endpoints) == 1 && !c.autoDowngrade {
+		tr, _, err := c.attempt(ctx, &formBody{c: c, tc: tc, audio: audioOnce(h)}, tc, endpoints[0], st)
+		st.record(err)
+		if err != nil {
+			return nil, err
+		}
+		tr.Meta.Endpoint = endpoints[0].name()
+		return tr, nil
+	}
 
-	req.Header.Set("Content-Type", mp.FormDataContentType())
-	req.Header.Set("Accept-Encoding", "gzip, deflate")
-	req.Header.Set("Accept", "*/*")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	// All attempts of the call share a key so the server can deduplicate them.
+	if tc.IdempotencyKey == "" {
+		tc.IdempotencyKey = newUUID()
+	}
+
+	body := &formBody{c: c, tc: tc, audio: newReplayBody(h).reader}
+	var errs []error
+	for i, ep := range endpoints {
+		tr, retry, err := c.retry(ctx, body, tc, ep, maxRetries, st)
+		if err == nil {
+			tr.Meta.Endpoint = ep.name()
+			return tr, nil
+		}
+		if len(endpoints) == 1 {
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", ep.name(), err))
+		if !failover(retry, err) || i == len(endpoints)-1 {
+			break
+		}
+		if c.logger != nil {
+			c.logger.Warn("transcribe failing over", "request_id", tc.RequestID, "next_endpoint", endpoints[i+1].name())
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// retry sends the request to ep, retrying failed attempts up
+// to maxRetries times. It reports whether the last failure was retryable.
+// The attempts are recorded in st.
+func (c *Client) retry(ctx context.Context, body *formBody, tc *transcribe.TranscribeConfig, ep Endpoint, maxRetries int, st *callState) (*models.TranscribeResponse, bool, error) {
+	var errs []error
+	for i := 0; ; i++ {
+		tr, retry, err := c.attempt(ctx, body, tc, ep, st)
+		st.record(err)
+		if c.autoDowngrade && tc.ResponseFormat != "json" && unsupportedFormat(err) {
+			if c.logger != nil {
+				c.logger.Warn("response format not supported, retrying with json", "request_id", tc.RequestID, "response_format", tc.ResponseFormat)
+			}
+			tc.ResponseFormat = "json"
+			i--
+			continue
+		}
+		if err == nil || !retry {
+			return tr, retry, err
+		}
+		errs = append(errs, err)
+		if i == maxRetries {
+			if maxRetries == 0 {
+				return nil, retry, err
+			}
+			return nil, retry, &RetryExhaustedError{Errors: errs}
+		}
+		wait := retryDelay(i, err)
+		if tc.OnRetry != nil {
+			if err := onRetry(tc.OnRetry, i+1, err, wait); err != nil {
+				return nil, false, err
+			}
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, false, err
+		}
+	}
+}
 
-	resp, err := c.httpClient.Do(req)
+// attempt sends a single request of the call st with a new body from body
+// to ep. It reports whether a failed attempt may be retried.
+func (c *Client) attempt(ctx context.Context, body *formBody, tc *transcribe.TranscribeConfig, ep Endpoint, st *callState) (*models.TranscribeResponse, bool, error) {
+	form, contentType, err := body.open()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	defer resp.Body.Close()
+	defer body.finish()
 
-	var r io.Reader
-	switch strings.ToLower(resp.Header.Get("Content-Encoding")) {
-	case "gzip":
-		r, err = gzip.NewReader(resp.Body)
+	var trace *connTrace
+	if c.connectionStats {
+		ctx, trace = withConnTrace(ctx)
+	}
+	var prog progress
+	ctx = withProgress(ctx, &prog)
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.urlFor(ep.BaseURL, st.path), prog.body(form), ep.APIKey)
+	if err != nil {
+		return nil, false, err
+	}
+	// Replaying the body lets the transport follow 307 and 308 redirects.
+	req.GetBody = func() (io.ReadCloser, error) {
+		r, _, err := body.open()
 		if err != nil {
 			return nil, err
 		}
-		defer r.(*gzip.Reader).Close()
-	case "deflate":
-		r = flate.NewReader(resp.Body)
-		defer r.(io.ReadCloser).Close()
-	default:
-		r = resp.Body
+		prog.sent.Store(0)
+		return io.NopCloser(prog.body(r)), nil
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	if !c.noCompression {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+	req.Header.Set("Accept", c.acceptFor(tc.ResponseFormat))
+	req.Header.Set("X-Request-ID", tc.RequestID)
+	if tc.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", tc.IdempotencyKey)
 	}
 
+	if c.logger != nil {
+		c.logger.Debug("sending request", "request_id", tc.RequestID, "url", req.URL.Redacted(), "headers", c.redactHeaders(req.Header))
+	}
+	start := time.Now()
+	resp, err := c.do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		tErr := c.transportError(ctx, &prog, req, err, elapsed, tc.File, st.size)
+		if c.logger != nil {
+			c.logger.Error("transcribe request failed", "request_id", tc.RequestID, "file", tc.File, "duration", elapsed, "error", tErr)
+		}
+		// A failure to read the audio is not worth retrying. Once ctx is done
+		// the audio may have been closed under the writer, so ctx's error is
+		// reported instead.
+		if ferr := body.finish(); ferr != nil && !errors.Is(ferr, io.ErrClosedPipe) && ctx.Err() == nil {
+			return nil, false, ferr
+		}
+		return nil, IsRetryable(err), tErr
+	}
+	defer resp.Body.Close()
+	c.updateRateLimit(resp.Header)
+
+	var r io.Reader = resp.Body
+	if !c.noCompression {
+		switch strings.ToLower(resp.Header.Get("Content-Encoding")) {
+		case "gzip":
+			r, err = gzip.NewReader(resp.Body)
+			if err != nil {
+				return nil, false, err
+			}
+			defer r.(*gzip.Reader).Close()
+		case "deflate":
+			r = flate.NewReader(resp.Body)
+			defer r.(io.ReadCloser).Close()
+		}
+	}
+	r = &limitReader{r: r, n: c.maxResponseSize}
+
 	if resp.StatusCode != http.StatusOK {
-		io.Copy(os.Stderr, r)
-		return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+		err := responseError(resp, r, elapsed)
+		if resp.StatusCode == http.StatusRequestEntityTooLarge {
+			err = fmt.Errorf("%w (request body of at least %d bytes; split the audio and transcribe the parts with transcribe.WithTimestampOffset): %w",
+				ErrPayloadTooLarge, prog.sent.Load(), err)
+		}
+		if c.logger != nil {
+			c.logger.Error("transcribe request failed", "request_id", tc.RequestID, "file", tc.File, "status", resp.StatusCode,
+				"duration", elapsed, "error", err)
+		}
+		return nil, IsRetryable(err), err
+	}
+	// A server may answer before reading the whole body; a response must not
+	// be taken as success if the audio could not be read in full.
+	if ferr := body.finish(); ferr != nil && !errors.Is(ferr, io.ErrClosedPipe) {
+		return nil, false, ferr
+	}
+	if c.logger != nil {
+		c.logger.Info("transcribe request completed", "request_id", tc.RequestID, "file", tc.File, "status", resp.StatusCode,
+			"duration", time.Since(start))
 	}
 
 	var tr models.TranscribeResponse
-	if err = json.NewDecoder(r).Decode(&tr); err != nil {
+	snippet := &prefixWriter{max: maxDecodeSnippet}
+	switch tc.ResponseFormat {
+	case "json", "verbose_json":
+		err = json.NewDecoder(io.TeeReader(r, snippet)).Decode(&tr)
+		if err == io.EOF {
+			// An empty body is reported by the check below.
+			err = nil
+		}
+		if err != nil && ctx.Err() == nil && !errors.Is(err, ErrResponseTooLarge) {
+			return nil, false, &DecodeError{ContentType: resp.Header.Get("Content-Type"), Snippet: snippet.buf, Err: err}
+		}
+	default:
+		var text []byte
+		text, err = io.ReadAll(io.TeeReader(r, snippet))
+		tr.Text = string(text)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, false, c.transportError(ctx, &prog, req, err, time.Since(start), tc.File, st.size)
+		}
+		return nil, false, err
+	}
+	if !tc.AllowEmptyResult && implausible(&tr) {
+		return nil, false, fmt.Errorf("%w (Content-Length %q, body %q)", ErrEmptyResponse, resp.Header.Get("Content-Length"), snippet.buf)
+	}
+	tr.RequestID = resp.Header.Get("x-request-id")
+	if trace != nil {
+		tr.Meta.Timings = trace.Timings()
+	}
+	return &tr, false, nil
+}
+
+// implausible reports whether a decoded response is empty or truncated: it
+// has neither text nor segments. The task is not checked, as some
+// OpenAI-compatible servers such as faster-whisper omit it.
+func implausible(tr *models.TranscribeResponse) bool {
+	return strings.TrimSpace(tr.Text) == "" && len(tr.Segments) == 0
+}
+
+// acceptFor returns the Accept header for the given response format.
+func (c *Client) acceptFor(format string) string {
+	switch {
+	case c.accept != "":
+		return c.accept
+	case format == "json" || format == "verbose_json":
+		return "application/json"
+	}
+	return "*/*"
+}
+
+// resolveAPIKey returns the per-request override if set, or asks the
+// credentials provider for a key.
+func (c *Client) resolveAPIKey(ctx context.Context, override string) (string, error) {
+	apiKey := override
+	if apiKey == "" {
+		key, err := c.apiKey(ctx)
+		if err != nil {
+			return "", fmt.Errorf("fetching API key: %w", err)
+		}
+		apiKey = key
+	}
+	if apiKey == "" {
+		return "", fmt.Errorf("%w (set %sAPI_KEY in env)", ErrMissingAPIKey, c.envPrefix)
+	}
+	return apiKey, nil
+}
+
+// apiKey asks the credentials provider for a key.
+func (c *Client) apiKey(ctx context.Context) (key string, err error) {
+	defer recoverCallback("credentials provider", &err)
+	return c.credentials.APIKey(ctx)
+}
+
+// onRetry calls the retry callback fn.
+func onRetry(fn func(int, error, time.Duration), attempt int, attemptErr error, wait time.Duration) (err error) {
+	defer recoverCallback("retry callback", &err)
+	fn(attempt, attemptErr, wait)
+	return nil
+}
+
+// newRequest creates an authenticated request for the given URL.
+func (c *Client) newRequest(ctx context.Context, method, rawURL string, body io.Reader, apiKey string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
 		return nil, err
 	}
-	return &tr, nil
+	for k, vs := range c.headers {
+		req.Header[k] = append([]string(nil), vs...)
+	}
+	if c.azureEndpoint != "" {
+		req.Header.Set("api-key", apiKey)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return req, nil
+}
+
+// offsetTimestamps adds d to every segment and word timestamp of tr.
+func offsetTimestamps(tr *models.TranscribeResponse, d time.Duration) {
+	secs := d.Seconds()
+	for i := range tr.Segments {
+		seg := &tr.Segments[i]
+		seg.Start += secs
+		seg.End += secs
+		for j := range seg.Words {
+			seg.Words[j].Start += secs
+			seg.Words[j].End += secs
+		}
+	}
+	for i := range tr.Words {
+		tr.Words[i].Start += secs
+		tr.Words[i].End += secs
+	}
+}
+
+// charsPerToken approximates the number of characters in a prompt token.
+const charsPerToken = 4
+
+// truncatePrompt keeps the end of prompt within roughly the given number of
+// tokens, cut at the first word boundary, since Whisper only considers the
+// last prompt tokens.
+func truncatePrompt(prompt string, tokens int) string {
+	limit := tokens * charsPerToken
+	if len(prompt) <= limit {
+		return prompt
+	}
+	start := len(prompt) - limit
+	cut := strings.IndexFunc(prompt[start-1:], unicode.IsSpace)
+	if cut >= 0 {
+		cut += start - 1
+	} else {
+		for cut = start; cut < len(prompt) && !utf8.RuneStart(prompt[cut]); cut++ {
+		}
+	}
+	return strings.TrimLeftFunc(prompt[cut:], unicode.IsSpace)
+}
+
+// limitReader reads at most n bytes from r and fails with ErrResponseTooLarge
+// if r has more data than that.
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) > l.n {
+		n, l.n = int(l.n), 0
+		return n, ErrResponseTooLarge
+	}
+	l.n -= int64(n)
+	return n, err
 }
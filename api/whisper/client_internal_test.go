@@ -0,0 +1,24 @@
+package whisper
+
+import "testing"
+
+func TestTruncatePrompt(t *testing.T) {
+	tests := []struct {
+		prompt string
+		tokens int
+		want   string
+	}{
+		{"short prompt", 10, "short prompt"},
+		{"one two three four five", 3, "four five"},
+		{"one two three four five", 2, "five"},
+		{"one two three four fives", 2, "fives"},
+		{"abcdefghijklmnop", 2, "ijklmnop"},
+		{"ééééa", 1, "éa"},
+		{"Glossary:\nKubernetes\nPostgreSQL\n", 5, "PostgreSQL\n"},
+	}
+	for _, tt := range tests {
+		if got := truncatePrompt(tt.prompt, tt.tokens); got != tt.want {
+			t.Errorf("truncatePrompt(%q, %d) = %q, want %q", tt.prompt, tt.tokens, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,795 @@
+package whisper_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// tracer records the names of the middlewares and transports a request
+// passes through.
+type tracer struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (tc *tracer) middleware(name string) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return whisper.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			tc.record(name)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func (tc *tracer) transport(name string) *http.Client {
+	return &http.Client{Transport: tc.middleware(name)(http.DefaultTransport)}
+}
+
+func (tc *tracer) record(name string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.calls = append(tc.calls, name)
+}
+
+func (tc *tracer) take() []string {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	calls := tc.calls
+	tc.calls = nil
+	return calls
+}
+
+func TestCloneMiddlewares(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+
+	var tc tracer
+	c := whisper.NewClient(
+		whisper.WithKey("k"),
+		whisper.WithBaseURL(srv.URL),
+		whisper.WithHTTPClient(tc.transport("base")),
+		whisper.WithRoundTripper(tc.middleware("a")),
+	)
+
+	tests := []struct {
+		name  string
+		c     *whisper.Client
+		calls []string
+	}{
+		{"original", c, []string{"a", "base"}},
+		{"plain clone", c.Clone(), []string{"a", "base"}},
+		{"added middleware", c.Clone(whisper.WithRoundTripper(tc.middleware("b"))), []string{"a", "b", "base"}},
+		{"new HTTP client", c.Clone(whisper.WithHTTPClient(tc.transport("other"))), []string{"a", "other"}},
+		{
+			"new HTTP client and middleware",
+			c.Clone(whisper.WithHTTPClient(tc.transport("other")), whisper.WithRoundTripper(tc.middleware("b"))),
+			[]string{"a", "b", "other"},
+		},
+		{"clone of clone", c.Clone(whisper.WithRoundTripper(tc.middleware("b"))).Clone(), []string{"a", "b", "base"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc.take()
+			if _, err := tt.c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+				t.Fatal(err)
+			}
+			if got := tc.take(); !reflect.DeepEqual(got, tt.calls) {
+				t.Errorf("request went through %q, want %q", got, tt.calls)
+			}
+		})
+	}
+}
+
+func TestCloneWithTransport(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+
+	var tc tracer
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithRoundTripper(tc.middleware("a")))
+	transport := &http.Transport{Proxy: func(*http.Request) (*url.URL, error) {
+		tc.record("transport")
+		return nil, nil
+	}}
+	clone := c.Clone(whisper.WithTransport(transport))
+	if _, err := clone.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tc.take(), []string{"a", "transport"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("request went through %q, want %q", got, want)
+	}
+}
+
+func TestRoundTripperOrder(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+
+	var tc tracer
+	bases := map[string]struct {
+		opts  []whisper.ClientOption
+		calls []string
+	}{
+		"default client": {nil, []string{"a", "b"}},
+		"WithHTTPClient": {[]whisper.ClientOption{whisper.WithHTTPClient(tc.transport("base"))}, []string{"a", "b", "base"}},
+	}
+	for name, base := range bases {
+		t.Run(name, func(t *testing.T) {
+			opts := append([]whisper.ClientOption{
+				whisper.WithKey("k"),
+				whisper.WithBaseURL(srv.URL),
+				whisper.WithRoundTripper(tc.middleware("a")),
+				whisper.WithRoundTripper(tc.middleware("b")),
+			}, base.opts...)
+			c := whisper.NewClient(opts...)
+			tc.take()
+			if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+				t.Fatal(err)
+			}
+			if got := tc.take(); !reflect.DeepEqual(got, base.calls) {
+				t.Errorf("request went through %q, want %q", got, base.calls)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var got string
+	rt := whisper.RequestIDMiddleware(whisper.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get("X-Request-ID")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if got == "" {
+		t.Error("RequestIDMiddleware did not set X-Request-ID")
+	}
+
+	req.Header.Set("X-Request-ID", "req-1")
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if got != "req-1" {
+		t.Errorf("X-Request-ID = %q, want the caller's req-1", got)
+	}
+}
+
+func TestPromptFileTruncation(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	path := filepath.Join(t.TempDir(), "prompt.txt")
+	if err := os.WriteFile(path, []byte("Glossary:\nKubernetes, kubectl\nPostgreSQL, psql\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opts []transcribe.TranscribeOption
+		want string
+	}{
+		{"whole file", nil, "Glossary:\nKubernetes, kubectl\nPostgreSQL, psql\n"},
+		{"truncated", []transcribe.TranscribeOption{transcribe.WithPromptTokenLimit(5)}, "PostgreSQL, psql\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]transcribe.TranscribeOption{transcribe.WithFile("a.wav"), transcribe.WithPromptFile(path)}, tt.opts...)
+			if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), opts...); err != nil {
+				t.Fatal(err)
+			}
+			reqs := srv.Requests()
+			if got := reqs[len(reqs)-1].Fields["prompt"]; len(got) != 1 || got[0] != tt.want {
+				t.Errorf("prompt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithPromptFile(path+".missing"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing prompt file: %v, want an os.ErrNotExist error", err)
+	}
+}
+
+func TestReplacements(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("we gonna ship kubernetes"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"),
+		transcribe.WithReplacements(map[string]string{"gonna": "are going to"}),
+		transcribe.WithReplacements(map[string]string{"kubernetes": "Kubernetes"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "we are going to ship Kubernetes"; tr.Text != want {
+		t.Errorf("Text = %q, want %q", tr.Text, want)
+	}
+
+	_, err = c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"),
+		transcribe.WithReplacements(map[string]string{"go": "Go"}),
+		transcribe.WithReplacements(map[string]string{"GO": "golang"}))
+	if !errors.Is(err, models.ErrReplacementConflict) {
+		t.Errorf("conflicting replacements: %v, want ErrReplacementConflict", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("server received %d requests, want the conflict rejected before sending", n)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	text := strings.Repeat("la ", 20000)
+	stream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"text":"`)
+		for i := 0; i < len(text); i += 1024 {
+			io.WriteString(w, text[i:min(i+1024, len(text))])
+			w.(http.Flusher).Flush()
+		}
+		io.WriteString(w, `"}`)
+	}))
+	defer stream.Close()
+	compressed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		io.WriteString(zw, `{"text":"`+text+`"}`)
+		zw.Close()
+	}))
+	defer compressed.Close()
+
+	for _, srv := range []*httptest.Server{stream, compressed} {
+		c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithMaxResponseSize(4096))
+		_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+		if !errors.Is(err, whisper.ErrResponseTooLarge) {
+			t.Errorf("Transcribe() = %v, want ErrResponseTooLarge", err)
+		}
+
+		c = whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+		tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+		if err != nil {
+			t.Fatalf("Transcribe() with the default limit: %v", err)
+		}
+		if tr.Text != text {
+			t.Errorf("Text has %d bytes, want %d", len(tr.Text), len(text))
+		}
+	}
+}
+
+func TestAPIKeyOverrideConcurrent(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("sk-default"), whisper.WithBaseURL(srv.URL))
+
+	keys := map[string]string{}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("req-%d", i)
+		key := fmt.Sprintf("sk-tenant-%d", i%2)
+		keys[id] = key
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithAPIKey(key), transcribe.WithRequestID(id))
+			if err != nil {
+				mu.Lock()
+				t.Error(err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	reqs := srv.Requests()
+	if len(reqs) != len(keys) {
+		t.Fatalf("server received %d requests, want %d", len(reqs), len(keys))
+	}
+	for _, req := range reqs {
+		id := req.Header.Get("X-Request-ID")
+		if got, want := req.Header.Get("Authorization"), "Bearer "+keys[id]; got != want {
+			t.Errorf("request %s sent %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestAPIKeyOverrideNotLeaked(t *testing.T) {
+	srv := whispertest.NewServer(whispertest.Error(http.StatusUnauthorized, "Incorrect API key provided: sk-override-secret-1234."))
+	defer srv.Close()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := whisper.NewClient(whisper.WithKey("sk-default"), whisper.WithBaseURL(srv.URL), whisper.WithLogger(logger))
+
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithAPIKey("sk-override-secret-1234"))
+	if err == nil {
+		t.Fatal("Transcribe() succeeded, want a 401 error")
+	}
+	if strings.Contains(err.Error(), "sk-override-secret-1234") {
+		t.Errorf("error leaks the key: %v", err)
+	}
+	if logs.Len() == 0 || strings.Contains(logs.String(), "sk-override-secret-1234") {
+		t.Errorf("logs leak the key or are empty:\n%s", logs.String())
+	}
+}
+
+func TestCloneLeavesParentUntouched(t *testing.T) {
+	parentSrv := whispertest.NewServer(jsonText("parent"))
+	defer parentSrv.Close()
+	cloneSrv := whispertest.NewServer(jsonText("clone"))
+	defer cloneSrv.Close()
+
+	parent := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(parentSrv.URL), whisper.WithHeaders(map[string]string{"X-Team": "a"}))
+	clone := parent.Clone(whisper.WithBaseURL(cloneSrv.URL), whisper.WithHeaders(map[string]string{"X-Team": "b"}), whisper.WithCostTracking(0.006))
+
+	for _, c := range []*whisper.Client{parent, clone} {
+		if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(parentSrv.Requests()); n != 1 {
+		t.Errorf("parent server received %d requests, want 1", n)
+	}
+	if n := len(cloneSrv.Requests()); n != 1 {
+		t.Fatalf("clone server received %d requests, want 1", n)
+	}
+	if got := parentSrv.Requests()[0].Header.Get("X-Team"); got != "a" {
+		t.Errorf("parent sent X-Team %q, want %q", got, "a")
+	}
+	if got := cloneSrv.Requests()[0].Header.Get("X-Team"); got != "b" {
+		t.Errorf("clone sent X-Team %q, want %q", got, "b")
+	}
+	if u := parent.Usage(); u.Requests != 0 {
+		t.Errorf("parent Usage() = %+v, want no cost tracking", u)
+	}
+}
+
+func TestTextNormalization(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("  Hello  there,   world. "))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithTextNormalization())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hello there, world."; tr.Text != want {
+		t.Errorf("Text = %q, want %q", tr.Text, want)
+	}
+	tr, err = c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "  Hello  there,   world. "; tr.Text != want {
+		t.Errorf("Text without normalization = %q, want %q", tr.Text, want)
+	}
+
+	upper := models.WithUnicodeNormalization(strings.ToUpper)
+	tr, err = c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithTextNormalization(upper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "HELLO THERE, WORLD."; tr.Text != want {
+		t.Errorf("Text with a unicode normalization = %q, want %q", tr.Text, want)
+	}
+}
+
+func TestResponseCompression(t *testing.T) {
+	const body = `{"text":"compressed hello"}`
+	var mu sync.Mutex
+	var acceptEncoding string
+	compressing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.Header.Get("Accept-Encoding"), "deflate"):
+			w.Header().Set("Content-Encoding", "deflate")
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			io.WriteString(fw, body)
+			fw.Close()
+		case strings.Contains(r.Header.Get("Accept-Encoding"), "gzip"):
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			io.WriteString(zw, body)
+			zw.Close()
+		default:
+			io.WriteString(w, body)
+		}
+	}))
+	defer compressing.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+	defer plain.Close()
+
+	for _, enabled := range []bool{true, false} {
+		for _, srv := range []*httptest.Server{compressing, plain} {
+			c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithResponseCompression(enabled))
+			tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+			if err != nil {
+				t.Errorf("compression %v: %v", enabled, err)
+				continue
+			}
+			if tr.Text != "compressed hello" {
+				t.Errorf("compression %v: Text = %q", enabled, tr.Text)
+			}
+		}
+		mu.Lock()
+		got := acceptEncoding
+		mu.Unlock()
+		if want := map[bool]string{true: "gzip, deflate", false: "gzip"}[enabled]; got != want {
+			t.Errorf("compression %v: Accept-Encoding = %q, want %q", enabled, got, want)
+		}
+	}
+}
+
+func TestAcceptHeader(t *testing.T) {
+	var mu sync.Mutex
+	var accept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		accept = r.Header.Get("Accept")
+		mu.Unlock()
+		body := "hello"
+		if f := r.FormValue("response_format"); f == "" || f == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			body = `{"text":"hello"}`
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		io.WriteString(zw, body)
+		zw.Close()
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		accept, format, want string
+	}{
+		{"", "json", "application/json"},
+		{"", "text", "*/*"},
+		{"application/vnd.whisper+json", "json", "application/vnd.whisper+json"},
+		{"text/plain", "text", "text/plain"},
+	}
+	for _, tt := range tests {
+		c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithAcceptHeader(tt.accept))
+		tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithResponseFormat(tt.format))
+		if err != nil {
+			t.Errorf("Accept %q, format %s: %v", tt.accept, tt.format, err)
+			continue
+		}
+		if tr.Text != "hello" {
+			t.Errorf("Accept %q, format %s: Text = %q, want the decompressed text", tt.accept, tt.format, tr.Text)
+		}
+		mu.Lock()
+		got := accept
+		mu.Unlock()
+		if got != tt.want {
+			t.Errorf("Accept %q, format %s: sent Accept %q, want %q", tt.accept, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultModelPrecedence(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	tests := []struct {
+		name   string
+		client []whisper.ClientOption
+		opts   []transcribe.TranscribeOption
+		want   string
+	}{
+		{"package default", nil, nil, whisper.DefaultModel},
+		{"client default", []whisper.ClientOption{whisper.WithDefaultModel("client-model")}, nil, "client-model"},
+		{
+			"request option",
+			[]whisper.ClientOption{whisper.WithDefaultModel("client-model")},
+			[]transcribe.TranscribeOption{transcribe.WithModel("request-model")},
+			"request-model",
+		},
+		{"request option without client default", nil, []transcribe.TranscribeOption{transcribe.WithModel("request-model")}, "request-model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := whisper.NewClient(append([]whisper.ClientOption{whisper.WithKey("k"), whisper.WithBaseURL(srv.URL)}, tt.client...)...)
+			opts := append([]transcribe.TranscribeOption{transcribe.WithFile("a.wav")}, tt.opts...)
+			if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), opts...); err != nil {
+				t.Fatal(err)
+			}
+			reqs := srv.Requests()
+			if got := reqs[len(reqs)-1].Fields["model"]; len(got) != 1 || got[0] != tt.want {
+				t.Errorf("model = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// A per-request model does not change the client default.
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithDefaultModel("client-model"))
+	for _, opts := range [][]transcribe.TranscribeOption{{transcribe.WithModel("request-model")}, nil} {
+		if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), append(opts, transcribe.WithFile("a.wav"))...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reqs := srv.Requests()
+	if got := reqs[len(reqs)-1].Fields["model"]; len(got) != 1 || got[0] != "client-model" {
+		t.Errorf("model after a per-request override = %q, want the client default", got)
+	}
+}
+
+func TestDefaultOptionsPrecedence(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithDefaultOptions(
+		transcribe.WithModel("default-model"),
+		transcribe.WithLanguage("de"),
+		transcribe.WithPrompt("default prompt"),
+	))
+
+	tests := []struct {
+		name                    string
+		opts                    []transcribe.TranscribeOption
+		model, language, prompt string
+	}{
+		{"defaults", nil, "default-model", "de", "default prompt"},
+		{
+			"per-request overrides",
+			[]transcribe.TranscribeOption{transcribe.WithModel("request-model"), transcribe.WithLanguage("fr"), transcribe.WithPrompt("request prompt")},
+			"request-model", "fr", "request prompt",
+		},
+		{"partial override", []transcribe.TranscribeOption{transcribe.WithLanguage("es")}, "default-model", "es", "default prompt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]transcribe.TranscribeOption{transcribe.WithFile("a.wav")}, tt.opts...)
+			if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), opts...); err != nil {
+				t.Fatal(err)
+			}
+			reqs := srv.Requests()
+			fields := reqs[len(reqs)-1].Fields
+			for name, want := range map[string]string{"model": tt.model, "language": tt.language, "prompt": tt.prompt} {
+				if got := fields[name]; len(got) != 1 || got[0] != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTimestampOffset(t *testing.T) {
+	srv := whispertest.NewServer(whispertest.Transcript(&models.TranscribeResponse{
+		Task:     "transcribe",
+		Text:     "Hello there.",
+		Duration: 4,
+		Segments: []models.Segment{
+			{Start: 0, End: 1.5, Text: " Hello", Words: []models.Word{{Word: "Hello", Start: 0.2, End: 1.4}}},
+			{Start: 1.5, End: 4, Text: " there."},
+		},
+		Words: []models.Word{{Word: "Hello", Start: 0.2, End: 1.4}, {Word: "there.", Start: 1.6, End: 3.9}},
+	}))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"),
+		transcribe.WithResponseFormat("verbose_json"), transcribe.WithTimestampOffset(90*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Duration != 4 {
+		t.Errorf("Duration = %v, want 4 unchanged", tr.Duration)
+	}
+	var got [][2]float64
+	for _, s := range tr.Segments {
+		got = append(got, [2]float64{s.Start, s.End})
+	}
+	for _, w := range tr.Segments[0].Words {
+		got = append(got, [2]float64{w.Start, w.End})
+	}
+	for _, w := range tr.Words {
+		got = append(got, [2]float64{w.Start, w.End})
+	}
+	want := [][2]float64{{90, 91.5}, {91.5, 94}, {90.2, 91.4}, {90.2, 91.4}, {91.6, 93.9}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("timestamps = %v, want %v", got, want)
+	}
+}
+
+// isOpen reports whether the process has a file descriptor open for path.
+func isOpen(t *testing.T, path string) bool {
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skipf("listing open files: %v", err)
+	}
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil && target == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTranscribeFileClosedOnCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "talk.wav")
+	if err := os.WriteFile(path, wavFile(5), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	// The request stays in flight until the file is closed, so the file
+	// must be closed by the cancellation rather than by the call returning.
+	closed := make(chan bool, 1)
+	stall := func(http.RoundTripper) http.RoundTripper {
+		return whisper.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !isOpen(t, path) {
+				closed <- false
+				return nil, errors.New("file not open during the request")
+			}
+			cancel()
+			deadline := time.Now().Add(2 * time.Second)
+			for isOpen(t, path) && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			closed <- !isOpen(t, path)
+			return nil, req.Context().Err()
+		})
+	}
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL("http://whisper.invalid"), whisper.WithRoundTripper(stall))
+
+	if _, err := c.TranscribeFileContext(ctx, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("TranscribeFileContext() = %v, want context.Canceled", err)
+	}
+	if !<-closed {
+		t.Error("file still open after the context was canceled")
+	}
+	if isOpen(t, path) {
+		t.Error("file still open after TranscribeFileContext returned")
+	}
+}
+
+func TestResponseBookkeeping(t *testing.T) {
+	resp := whispertest.Response{
+		Header: http.Header{"Content-Type": {"application/json"}, "X-Request-Id": {"req_7f3a"}},
+		Body:   `{"text":"hello","SourceFile":"from-body.wav","RequestID":"from-body"}`,
+	}
+	srv := whispertest.NewServer(resp, jsonText("no request id"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	path := filepath.Join(t.TempDir(), "standup.wav")
+	if err := os.WriteFile(path, wavFile(1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := c.TranscribeFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.SourceFile != "standup.wav" || tr.RequestID != "req_7f3a" {
+		t.Errorf("SourceFile, RequestID = %q, %q; want standup.wav, req_7f3a", tr.SourceFile, tr.RequestID)
+	}
+
+	tr, err = c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("b.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.SourceFile != "b.wav" || tr.RequestID != "" {
+		t.Errorf("SourceFile, RequestID = %q, %q; want b.wav and none", tr.SourceFile, tr.RequestID)
+	}
+}
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		base, rel string
+		opts      []whisper.ClientOption
+		want      string
+	}{
+		{"", "audio/transcriptions", nil, "https://api.openai.com/v1/audio/transcriptions"},
+		{"https://gw.corp/openai/v1", "audio/transcriptions", nil, "https://gw.corp/openai/v1/audio/transcriptions"},
+		{"https://gw.corp/openai/v1/", "audio/transcriptions", nil, "https://gw.corp/openai/v1/audio/transcriptions"},
+		{"https://gw.corp/openai/v1", "/audio/transcriptions", nil, "https://gw.corp/openai/v1/audio/transcriptions"},
+		{"https://gw.corp/openai/v1/", "/audio/transcriptions", nil, "https://gw.corp/openai/v1/audio/transcriptions"},
+		{"https://gw.corp", "audio/transcriptions", nil, "https://gw.corp/audio/transcriptions"},
+		{"https://gw.corp/openai?api-version=2024-06-01", "audio/translations", nil, "https://gw.corp/openai/audio/translations?api-version=2024-06-01"},
+		{"https://gw.corp/openai/?api-version=1", "models?limit=2", nil, "https://gw.corp/openai/models?api-version=1&limit=2"},
+		{"https://gw.corp/v1", "https://other.corp/v2/models", nil, "https://other.corp/v2/models"},
+		{"https://gw.corp/v1", "models", []whisper.ClientOption{whisper.WithQuery("api-version", "2")}, "https://gw.corp/v1/models?api-version=2"},
+		{"https://gw.corp/v1", "https://GW.corp/v2/models", []whisper.ClientOption{whisper.WithQuery("api-version", "2")}, "https://GW.corp/v2/models?api-version=2"},
+		{"https://gw.corp/v1", "https://other.corp/v2/models?a=1", []whisper.ClientOption{whisper.WithQuery("api-version", "2")}, "https://other.corp/v2/models?a=1"},
+		{"https://gw.corp:8443/v1", "https://gw.corp/v2/models", []whisper.ClientOption{whisper.WithQuery("api-version", "2")}, "https://gw.corp/v2/models"},
+		{"", "https://api.openai.com/v1/models", []whisper.ClientOption{whisper.WithQuery("api-version", "2")}, "https://api.openai.com/v1/models?api-version=2"},
+	}
+	for _, tt := range tests {
+		c := whisper.NewClient(append([]whisper.ClientOption{whisper.WithBaseURL(tt.base)}, tt.opts...)...)
+		if got := c.URL(tt.rel); got != tt.want {
+			t.Errorf("URL(%q) with base %q = %q, want %q", tt.rel, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestVerboseResponseWithoutTask(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("..", "..", "models", "testdata", "faster_whisper.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := whispertest.NewServer(whispertest.Response{Header: http.Header{"Content-Type": {"application/json"}}, Body: string(body)})
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithVerbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Text != "Transcribed locally." || tr.Task != "" || tr.Duration != 5.25 {
+		t.Errorf("decoded %+v", tr)
+	}
+}
+
+func TestInvalidTypedResponseFormat(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithResponseFormatTyped("yaml"))
+	if !errors.Is(err, transcribe.ErrInvalidResponseFormat) {
+		t.Errorf("Transcribe() = %v, want ErrInvalidResponseFormat", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("server received %d requests, want none", n)
+	}
+
+	if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithResponseFormatTyped(transcribe.FormatJSON)); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Requests()[0].Fields["response_format"]; len(got) != 1 || got[0] != "json" {
+		t.Errorf("response_format = %q, want json", got)
+	}
+}
+
+func TestResponseFormatField(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []transcribe.TranscribeOption
+		want string
+	}{
+		{"default", nil, whisper.DefaultResponseFormat},
+		{"verbose", []transcribe.TranscribeOption{transcribe.WithVerbose()}, "verbose_json"},
+		{"json", []transcribe.TranscribeOption{transcribe.WithResponseFormatJSON()}, "json"},
+		{"last wins", []transcribe.TranscribeOption{transcribe.WithVerbose(), transcribe.WithResponseFormatJSON()}, "json"},
+	}
+	for _, tt := range tests {
+		srv := whispertest.NewServer(jsonText("hello"))
+		c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+		_, err := c.Transcribe(bytes.NewReader(wavFile(1)), append([]transcribe.TranscribeOption{transcribe.WithFile("a.wav")}, tt.opts...)...)
+		reqs := srv.Requests()
+		srv.Close()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := reqs[0].Fields["response_format"]; len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%s: response_format = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if whisper.DefaultResponseFormat != "json" {
+		t.Errorf("DefaultResponseFormat = %q, want json", whisper.DefaultResponseFormat)
+	}
+}
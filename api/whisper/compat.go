@@ -0,0 +1,110 @@
+package whisper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// OptionConflict is a pair of options that cannot be used together.
+type OptionConflict struct {
+	Option   string
+	Conflict string
+	Reason   string
+	// Err is a sentinel error for the conflict, if there is one.
+	Err error
+}
+
+// IncompatibleOptionsError is returned before anything is uploaded when the
+// options of a request contradict each other.
+type IncompatibleOptionsError struct {
+	Conflicts []OptionConflict
+}
+
+func (e *IncompatibleOptionsError) Error() string {
+	var b strings.Builder
+	b.WriteString("incompatible options: ")
+	for i, c := range e.Conflicts {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(&b, "%s with %s (%s)", c.Option, c.Conflict, c.Reason)
+	}
+	return b.String()
+}
+
+// Unwrap returns the sentinel errors of the conflicts, so that errors.Is
+// matches e.g. ErrLanguageNotAllowed.
+func (e *IncompatibleOptionsError) Unwrap() []error {
+	var errs []error
+	for _, c := range e.Conflicts {
+		if c.Err != nil {
+			errs = append(errs, c.Err)
+		}
+	}
+	return errs
+}
+
+// compatRule describes a known-bad combination of options. check returns
+// the conflicting setting if the rule is violated for a request to path.
+type compatRule struct {
+	option string
+	reason string
+	err    error
+	check  func(path string, tc *transcribe.TranscribeConfig) (conflict string, violated bool)
+}
+
+// isGPT4oModel reports whether model is one of the gpt-4o transcription models.
+func isGPT4oModel(model string) bool {
+	return strings.HasPrefix(model, "gpt-4o")
+}
+
+// compatRules are the checks applied by checkCompatibility.
+var compatRules = []compatRule{
+	{
+		option: "timestamp_granularities",
+		reason: "requires the verbose_json response format",
+		check: func(_ string, tc *transcribe.TranscribeConfig) (string, bool) {
+			return "response_format=" + tc.ResponseFormat, len(tc.TimestampGranularities) > 0 && tc.ResponseFormat != "verbose_json"
+		},
+	},
+	{
+		option: "include[]=logprobs",
+		reason: "only supported by the gpt-4o transcription models",
+		check: func(_ string, tc *transcribe.TranscribeConfig) (string, bool) {
+			return "model=" + tc.Model, slices.Contains(tc.Include, "logprobs") && !isGPT4oModel(tc.Model)
+		},
+	},
+	{
+		option: "response_format",
+		reason: "the gpt-4o transcription models only support the json and text response formats",
+		check: func(_ string, tc *transcribe.TranscribeConfig) (string, bool) {
+			return "model=" + tc.Model, isGPT4oModel(tc.Model) && tc.ResponseFormat != "json" && tc.ResponseFormat != "text"
+		},
+	},
+	{
+		option: "language",
+		reason: "translations are always into English",
+		err:    ErrLanguageNotAllowed,
+		check: func(path string, tc *transcribe.TranscribeConfig) (string, bool) {
+			return "translation", path == translationsPath && tc.Language != ""
+		},
+	},
+}
+
+// checkCompatibility returns an *IncompatibleOptionsError listing every rule
+// the request to path violates, or nil.
+func checkCompatibility(path string, tc *transcribe.TranscribeConfig) error {
+	var conflicts []OptionConflict
+	for _, rule := range compatRules {
+		if conflict, violated := rule.check(path, tc); violated {
+			conflicts = append(conflicts, OptionConflict{Option: rule.option, Conflict: conflict, Reason: rule.reason, Err: rule.err})
+		}
+	}
+	if len(conflicts) > 0 {
+		return &IncompatibleOptionsError{Conflicts: conflicts}
+	}
+	return nil
+}
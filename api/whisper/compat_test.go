@@ -0,0 +1,119 @@
+package whisper
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		tc      transcribe.TranscribeConfig
+		options []string
+	}{
+		{
+			name: "defaults",
+			path: transcriptionsPath,
+			tc:   transcribe.TranscribeConfig{Model: "whisper-1", ResponseFormat: "json"},
+		},
+		{
+			name: "granularities with verbose_json",
+			path: transcriptionsPath,
+			tc:   transcribe.TranscribeConfig{Model: "whisper-1", ResponseFormat: "verbose_json", TimestampGranularities: []string{"word"}},
+		},
+		{
+			name:    "granularities with json",
+			path:    transcriptionsPath,
+			tc:      transcribe.TranscribeConfig{Model: "whisper-1", ResponseFormat: "json", TimestampGranularities: []string{"word"}},
+			options: []string{"timestamp_granularities"},
+		},
+		{
+			name: "logprobs with gpt-4o",
+			path: transcriptionsPath,
+			tc:   transcribe.TranscribeConfig{Model: "gpt-4o-transcribe", ResponseFormat: "json", Include: []string{"logprobs"}},
+		},
+		{
+			name:    "logprobs with whisper-1",
+			path:    transcriptionsPath,
+			tc:      transcribe.TranscribeConfig{Model: "whisper-1", ResponseFormat: "json", Include: []string{"logprobs"}},
+			options: []string{"include[]=logprobs"},
+		},
+		{
+			name:    "verbose_json with gpt-4o",
+			path:    transcriptionsPath,
+			tc:      transcribe.TranscribeConfig{Model: "gpt-4o-mini-transcribe", ResponseFormat: "verbose_json"},
+			options: []string{"response_format"},
+		},
+		{
+			name: "language for a transcription",
+			path: transcriptionsPath,
+			tc:   transcribe.TranscribeConfig{Model: "whisper-1", ResponseFormat: "json", Language: "de"},
+		},
+		{
+			name:    "language for a translation",
+			path:    translationsPath,
+			tc:      transcribe.TranscribeConfig{Model: "whisper-1", ResponseFormat: "json", Language: "de"},
+			options: []string{"language"},
+		},
+		{
+			name: "several conflicts",
+			path: translationsPath,
+			tc: transcribe.TranscribeConfig{Model: "whisper-1", ResponseFormat: "text", Language: "de",
+				TimestampGranularities: []string{"segment"}, Include: []string{"logprobs"}},
+			options: []string{"timestamp_granularities", "include[]=logprobs", "language"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCompatibility(tt.path, &tt.tc)
+			if tt.options == nil {
+				if err != nil {
+					t.Errorf("checkCompatibility() = %v, want nil", err)
+				}
+				return
+			}
+			var incompat *IncompatibleOptionsError
+			if !errors.As(err, &incompat) {
+				t.Fatalf("checkCompatibility() = %v, want an *IncompatibleOptionsError", err)
+			}
+			var options []string
+			for _, c := range incompat.Conflicts {
+				options = append(options, c.Option)
+			}
+			if !reflect.DeepEqual(options, tt.options) {
+				t.Errorf("conflicting options = %q, want %q", options, tt.options)
+			}
+		})
+	}
+}
+
+func TestIncompatibleOptionsNotSent(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer srv.Close()
+	c := NewClient(WithKey("k"), WithBaseURL(srv.URL))
+
+	_, err := c.Transcribe(bytes.NewReader([]byte("OggS audio")), transcribe.WithFile("a.ogg"),
+		transcribe.WithModel("gpt-4o-transcribe"), transcribe.WithResponseFormat("verbose_json"))
+	var incompat *IncompatibleOptionsError
+	if !errors.As(err, &incompat) {
+		t.Errorf("Transcribe() = %v, want an *IncompatibleOptionsError", err)
+	}
+	_, err = c.Translate(bytes.NewReader([]byte("OggS audio")), transcribe.WithFile("a.ogg"), transcribe.WithLanguage("de"))
+	if !errors.Is(err, ErrLanguageNotAllowed) {
+		t.Errorf("Translate() = %v, want ErrLanguageNotAllowed", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("server got %d requests, want none", n)
+	}
+}
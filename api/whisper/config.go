@@ -0,0 +1,149 @@
+package whisper
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// Providers supported by Config.Provider.
+const (
+	ProviderOpenAI = "openai"
+	ProviderAzure  = "azure"
+)
+
+// Config is a declarative alternative to ClientOptions, suitable for loading
+// from a file.
+type Config struct {
+	APIKey   string            `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+	BaseURL  string            `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+	Model    string            `json:"model,omitempty" yaml:"model,omitempty"`
+	Language string            `json:"language,omitempty" yaml:"language,omitempty"`
+	Timeout  Duration          `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retries  int               `json:"retries,omitempty" yaml:"retries,omitempty"`
+	Provider string            `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Azure    AzureConfig       `json:"azure,omitempty" yaml:"azure,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// EnvPrefix is the prefix of the API key and base URL environment
+	// variables, as for WithEnvPrefix. It defaults to DefaultEnvPrefix.
+	EnvPrefix string `json:"env_prefix,omitempty" yaml:"env_prefix,omitempty"`
+}
+
+// EnvName returns the name of the environment variable with the given
+// suffix under cfg.EnvPrefix, e.g. OPENAI_API_KEY for "API_KEY".
+func (cfg Config) EnvName(suffix string) string {
+	prefix := DefaultEnvPrefix
+	if cfg.EnvPrefix != "" {
+		prefix = normalizeEnvPrefix(cfg.EnvPrefix)
+	}
+	return prefix + suffix
+}
+
+// AzureConfig holds the Azure OpenAI settings used when Config.Provider is "azure".
+type AzureConfig struct {
+	Endpoint   string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Deployment string `json:"deployment,omitempty" yaml:"deployment,omitempty"`
+	APIVersion string `json:"api_version,omitempty" yaml:"api_version,omitempty"`
+}
+
+// Duration is a time.Duration that is encoded as a string such as "30s".
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// ConfigError reports an invalid Config field.
+type ConfigError struct {
+	Field string
+	Msg   string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid config field %s: %s", e.Field, e.Msg)
+}
+
+// Validate checks the Config for invalid or missing values.
+func (cfg Config) Validate() error {
+	if cfg.BaseURL != "" {
+		if u, err := url.Parse(cfg.BaseURL); err != nil || !u.IsAbs() {
+			return &ConfigError{Field: "BaseURL", Msg: "must be an absolute URL"}
+		}
+	}
+	if cfg.Timeout < 0 {
+		return &ConfigError{Field: "Timeout", Msg: "must not be negative"}
+	}
+	if cfg.Retries < 0 {
+		return &ConfigError{Field: "Retries", Msg: "must not be negative"}
+	}
+	switch cfg.Provider {
+	case "", ProviderOpenAI:
+	case ProviderAzure:
+		if cfg.Azure.Endpoint == "" {
+			return &ConfigError{Field: "Azure.Endpoint", Msg: "is required for the azure provider"}
+		}
+		if cfg.Azure.Deployment == "" {
+			return &ConfigError{Field: "Azure.Deployment", Msg: "is required for the azure provider"}
+		}
+		if cfg.Azure.APIVersion == "" {
+			return &ConfigError{Field: "Azure.APIVersion", Msg: "is required for the azure provider"}
+		}
+	default:
+		return &ConfigError{Field: "Provider", Msg: fmt.Sprintf("unknown provider %q", cfg.Provider)}
+	}
+	return nil
+}
+
+// NewClientFromConfig validates cfg and creates a Client from it.
+func NewClientFromConfig(cfg Config) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var opts []ClientOption
+	if cfg.EnvPrefix != "" {
+		opts = append(opts, WithEnvPrefix(cfg.EnvPrefix))
+	}
+	if cfg.APIKey != "" {
+		opts = append(opts, WithKey(cfg.APIKey))
+	}
+	if cfg.Provider == ProviderAzure {
+		opts = append(opts, WithAzure(cfg.Azure.Endpoint, cfg.Azure.Deployment, cfg.Azure.APIVersion))
+	} else if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(time.Duration(cfg.Timeout)))
+	}
+	if cfg.Retries > 0 {
+		opts = append(opts, WithMaxRetries(cfg.Retries))
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Model != "" {
+		opts = append(opts, WithDefaultModel(cfg.Model))
+	}
+
+	var defaults []transcribe.TranscribeOption
+	if cfg.Language != "" {
+		defaults = append(defaults, transcribe.WithLanguage(cfg.Language))
+	}
+	if len(defaults) > 0 {
+		opts = append(opts, WithDefaultOptions(defaults...))
+	}
+
+	return New(opts...)
+}
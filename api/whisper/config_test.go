@@ -0,0 +1,71 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestConfigValidate(t *testing.T) {
+	azure := whisper.AzureConfig{Endpoint: "https://res.openai.azure.com", Deployment: "whisper", APIVersion: "2024-06-01"}
+	tests := []struct {
+		cfg   whisper.Config
+		field string
+	}{
+		{whisper.Config{}, ""},
+		{whisper.Config{BaseURL: "https://example.com/v1", Retries: 2}, ""},
+		{whisper.Config{Provider: whisper.ProviderAzure, Azure: azure}, ""},
+		{whisper.Config{BaseURL: "example.com"}, "BaseURL"},
+		{whisper.Config{Timeout: -1}, "Timeout"},
+		{whisper.Config{Retries: -1}, "Retries"},
+		{whisper.Config{Provider: "other"}, "Provider"},
+		{whisper.Config{Provider: whisper.ProviderAzure, Azure: whisper.AzureConfig{Endpoint: azure.Endpoint}}, "Azure.Deployment"},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		var cerr *whisper.ConfigError
+		switch {
+		case tt.field == "" && err != nil:
+			t.Errorf("Validate(%+v) = %v, want nil", tt.cfg, err)
+		case tt.field != "" && (!errors.As(err, &cerr) || cerr.Field != tt.field):
+			t.Errorf("Validate(%+v) = %v, want an error for %s", tt.cfg, err, tt.field)
+		}
+	}
+}
+
+func TestConfigEnvName(t *testing.T) {
+	tests := []struct {
+		prefix, want string
+	}{
+		{"", "OPENAI_API_KEY"},
+		{"MYAPP", "MYAPP_API_KEY"},
+		{"MYAPP_", "MYAPP_API_KEY"},
+	}
+	for _, tt := range tests {
+		if got := (whisper.Config{EnvPrefix: tt.prefix}).EnvName("API_KEY"); got != tt.want {
+			t.Errorf("EnvName with prefix %q = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestNewClientFromConfigEnvPrefix(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	t.Setenv("OPENAI_API_KEY", "sk-openai")
+	t.Setenv("MYAPP_API_KEY", "sk-myapp")
+
+	c, err := whisper.NewClientFromConfig(whisper.Config{BaseURL: srv.URL, EnvPrefix: "MYAPP"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Requests()[0].Header.Get("Authorization"); got != "Bearer sk-myapp" {
+		t.Errorf("Authorization = %q, want the key from MYAPP_API_KEY", got)
+	}
+}
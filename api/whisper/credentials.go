@@ -0,0 +1,24 @@
+package whisper
+
+import "context"
+
+// CredentialsProvider supplies the API key used to authenticate a request.
+// It is called once per request, so implementations can rotate keys.
+type CredentialsProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// StaticCredentials is a CredentialsProvider that always returns the same key.
+type StaticCredentials string
+
+// APIKey returns the static key.
+func (s StaticCredentials) APIKey(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// WithCredentialsProvider sets the provider the Client asks for an API key on every request.
+func WithCredentialsProvider(p CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.credentials = p
+	}
+}
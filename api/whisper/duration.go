@@ -0,0 +1,148 @@
+package whisper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errUnknownDuration is returned when the duration of audio cannot be determined locally.
+var errUnknownDuration = errors.New("cannot determine audio duration")
+
+// probeDuration estimates the duration of audio from its first bytes and its
+// total size (-1 if unknown). WAV and MP3 are supported.
+func probeDuration(filename string, head []byte, size int64) (time.Duration, error) {
+	switch {
+	case bytes.HasPrefix(head, []byte("RIFF")):
+		return wavDuration(head, size)
+	case bytes.HasPrefix(head, []byte("ID3")), strings.EqualFold(filepath.Ext(filename), ".mp3"):
+		return mp3Duration(head, size)
+	}
+	return 0, errUnknownDuration
+}
+
+// WAVDuration computes the duration of a WAV file from its RIFF header,
+// using the byte rate of the fmt chunk and the size of the data chunk. It
+// reads at most the first 64 KiB of r and can be used with
+// WithDurationEstimator.
+func WAVDuration(r io.Reader) (time.Duration, error) {
+	head, err := io.ReadAll(io.LimitReader(r, probeSize))
+	if err != nil {
+		return 0, err
+	}
+	return wavDuration(head, -1)
+}
+
+// wavDuration computes the duration of a WAV file from the byte rate in its
+// fmt chunk and the size of its data chunk.
+func wavDuration(head []byte, size int64) (time.Duration, error) {
+	if len(head) < 12 || string(head[0:4]) != "RIFF" || string(head[8:12]) != "WAVE" {
+		return 0, errUnknownDuration
+	}
+	var byteRate uint32
+	for off := 12; off+8 <= len(head); {
+		id := string(head[off : off+4])
+		n := int64(binary.LittleEndian.Uint32(head[off+4 : off+8]))
+		body := head[off+8:]
+		switch id {
+		case "fmt ":
+			if len(body) < 12 {
+				return 0, errUnknownDuration
+			}
+			byteRate = binary.LittleEndian.Uint32(body[8:12])
+		case "data":
+			if byteRate == 0 {
+				return 0, errUnknownDuration
+			}
+			// Streamed WAVs leave the data size unset; use the file size instead.
+			if n == 0 || n == 0xFFFFFFFF {
+				if size < 0 {
+					return 0, errUnknownDuration
+				}
+				n = size - int64(off+8)
+			}
+			return time.Duration(float64(n) / float64(byteRate) * float64(time.Second)), nil
+		}
+		off += 8 + int(n) + int(n&1)
+	}
+	return 0, errUnknownDuration
+}
+
+var (
+	// mp3Bitrates holds the Layer III bitrates in kbit/s for MPEG-1 and MPEG-2/2.5.
+	mp3Bitrates = [2][16]int{
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	}
+	// mp3SampleRates is indexed by the version bits of the frame header.
+	mp3SampleRates = [4][3]int{
+		{11025, 12000, 8000},
+		{},
+		{22050, 24000, 16000},
+		{44100, 48000, 32000},
+	}
+)
+
+// mp3Duration computes the duration of an MP3 file from the frame count in a
+// Xing/Info header, or assumes a constant bitrate when there is none.
+func mp3Duration(head []byte, size int64) (time.Duration, error) {
+	off := 0
+	if len(head) >= 10 && string(head[0:3]) == "ID3" {
+		off = 10 + (int(head[6])<<21 | int(head[7])<<14 | int(head[8])<<7 | int(head[9]))
+		if head[5]&0x10 != 0 {
+			off += 10
+		}
+	}
+	for ; off+4 <= len(head); off++ {
+		if head[off] == 0xFF && head[off+1]&0xE0 == 0xE0 {
+			break
+		}
+	}
+	if off+4 > len(head) {
+		return 0, errUnknownDuration
+	}
+
+	hdr := binary.BigEndian.Uint32(head[off:])
+	version := hdr >> 19 & 3
+	layer := hdr >> 17 & 3
+	bitrateIdx := hdr >> 12 & 15
+	rateIdx := hdr >> 10 & 3
+	if version == 1 || layer != 1 || rateIdx == 3 {
+		return 0, errUnknownDuration
+	}
+	v := 1
+	samplesPerFrame := 576
+	if version == 3 {
+		v = 0
+		samplesPerFrame = 1152
+	}
+	sampleRate := mp3SampleRates[version][rateIdx]
+	bitrate := mp3Bitrates[v][bitrateIdx] * 1000
+
+	// The Xing/Info header sits after the side information in the first frame.
+	sideInfo := 32
+	mono := hdr>>6&3 == 3
+	switch {
+	case v == 0 && mono, v == 1 && !mono:
+		sideInfo = 17
+	case v == 1 && mono:
+		sideInfo = 9
+	}
+	if x := off + 4 + sideInfo; x+12 <= len(head) {
+		if tag := string(head[x : x+4]); tag == "Xing" || tag == "Info" {
+			if binary.BigEndian.Uint32(head[x+4:])&1 != 0 {
+				frames := binary.BigEndian.Uint32(head[x+8:])
+				return time.Duration(float64(frames) * float64(samplesPerFrame) / float64(sampleRate) * float64(time.Second)), nil
+			}
+		}
+	}
+
+	if size < 0 || bitrate == 0 {
+		return 0, errUnknownDuration
+	}
+	return time.Duration(float64(size-int64(off)) * 8 / float64(bitrate) * float64(time.Second)), nil
+}
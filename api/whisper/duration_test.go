@@ -0,0 +1,54 @@
+package whisper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// mp3Fixture returns an MP3 file with an ID3v2 tag whose last bytes look
+// like a frame sync, as embedded album art often does, followed by an
+// MPEG-1 Layer III frame with a Xing header counting frames frames.
+func mp3Fixture(frames uint32) []byte {
+	var b bytes.Buffer
+	// A tag of 8<<7 | 2 = 1026 bytes.
+	b.WriteString("ID3\x04\x00\x00\x00\x00\x08\x02")
+	tag := make([]byte, 1026)
+	tag[1024], tag[1025] = 0xFF, 0xF3
+	b.Write(tag)
+	// 128 kbit/s, 44.1 kHz, joint stereo.
+	b.Write([]byte{0xFF, 0xFB, 0x90, 0x40})
+	b.Write(make([]byte, 32))
+	b.WriteString("Xing")
+	binary.Write(&b, binary.BigEndian, uint32(1))
+	binary.Write(&b, binary.BigEndian, frames)
+	b.Write(make([]byte, 400))
+	return b.Bytes()
+}
+
+func TestMP3DurationID3Tag(t *testing.T) {
+	frames := uint32(1000)
+	data := mp3Fixture(frames)
+	d, err := probeDuration("audio.mp3", data, int64(len(data)))
+	if err != nil {
+		t.Fatalf("probeDuration: %v", err)
+	}
+	// 1152 samples per frame at 44.1 kHz.
+	want := time.Duration(float64(frames*1152) / 44100 * float64(time.Second))
+	if d != want {
+		t.Errorf("duration = %s, want %s", d, want)
+	}
+}
+
+func TestMP3DurationConstantBitrate(t *testing.T) {
+	// 128 kbit/s without a Xing header: 16000 bytes per second.
+	data := append([]byte{0xFF, 0xFB, 0x90, 0x40}, make([]byte, 16000*3-4)...)
+	d, err := probeDuration("audio.mp3", data, int64(len(data)))
+	if err != nil {
+		t.Fatalf("probeDuration: %v", err)
+	}
+	if d != 3*time.Second {
+		t.Errorf("duration = %s, want 3s", d)
+	}
+}
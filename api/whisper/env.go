@@ -0,0 +1,121 @@
+package whisper
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables NewClient consults.
+const DefaultEnvPrefix = "OPENAI_"
+
+// WithEnvPrefix sets the prefix of the environment variables NewClient reads
+// the API key and base URL from when they are not set explicitly, e.g.
+// "MYAPP_WHISPER" for MYAPP_WHISPER_API_KEY and MYAPP_WHISPER_BASE_URL.
+func WithEnvPrefix(prefix string) ClientOption {
+	return func(c *Client) {
+		c.envPrefix = normalizeEnvPrefix(prefix)
+	}
+}
+
+// normalizeEnvPrefix appends the separating underscore to a non-empty prefix.
+func normalizeEnvPrefix(prefix string) string {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	return prefix
+}
+
+// LoadDotEnv sets environment variables from a .env file. Variables that are
+// already set are left untouched. Lines may start with "export", values may
+// be single- or double-quoted, and # starts a comment. Single-quoted values
+// are taken literally; in double-quoted values \n, \r and \t are control
+// characters and a backslash before any other character, as in \$ or \",
+// escapes it.
+func LoadDotEnv(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		value, err = parseDotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+
+		if _, set := os.LookupEnv(key); !set {
+			if err := os.Setenv(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return sc.Err()
+}
+
+// parseDotEnvValue unquotes a .env value and strips trailing comments.
+func parseDotEnvValue(v string) (string, error) {
+	switch {
+	case strings.HasPrefix(v, `"`):
+		end := 1
+		for ; end < len(v); end++ {
+			if v[end] == '\\' {
+				end++
+			} else if v[end] == '"' {
+				break
+			}
+		}
+		if end >= len(v) {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return unescapeDotEnv(v[1:end]), nil
+	case strings.HasPrefix(v, "'"):
+		end := strings.Index(v[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return v[1 : end+1], nil
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v), nil
+}
+
+// unescapeDotEnv resolves the backslash escapes of a double-quoted .env
+// value.
+func unescapeDotEnv(v string) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		if v[i] != '\\' || i+1 == len(v) {
+			b.WriteByte(v[i])
+			continue
+		}
+		i++
+		switch v[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte(v[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package whisper_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+)
+
+// writeDotEnv writes content to a .env file and unsets the given variables
+// for the duration of the test.
+func writeDotEnv(t *testing.T, content string, keys ...string) string {
+	t.Helper()
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDotEnv(t *testing.T) {
+	tests := []struct {
+		name, line, want string
+	}{
+		{"plain", `DOTENV_V=sk-123`, "sk-123"},
+		{"spaces", `  DOTENV_V = sk-123  `, "sk-123"},
+		{"export", `export DOTENV_V=sk-123`, "sk-123"},
+		{"empty", `DOTENV_V=`, ""},
+		{"empty quoted", `DOTENV_V=""`, ""},
+		{"inline comment", `DOTENV_V=sk-123 # the key`, "sk-123"},
+		{"hash in value", `DOTENV_V=sk#123`, "sk#123"},
+		{"double quoted", `DOTENV_V="a # b"  # comment`, "a # b"},
+		{"single quoted", `DOTENV_V='a # b' # comment`, "a # b"},
+		{"single quoted literal", `DOTENV_V='a\n$b'`, `a\n$b`},
+		{"escapes", `DOTENV_V="line\nnext\ttab \"q\" \\ \$HOME"`, "line\nnext\ttab \"q\" \\ $HOME"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeDotEnv(t, "# comment\n\n"+tt.line+"\n", "DOTENV_V")
+			if err := whisper.LoadDotEnv(path); err != nil {
+				t.Fatal(err)
+			}
+			if got, ok := os.LookupEnv("DOTENV_V"); !ok || got != tt.want {
+				t.Errorf("DOTENV_V = %q (set %v), want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDotEnvKeepsExisting(t *testing.T) {
+	path := writeDotEnv(t, "DOTENV_SET=from-file\nDOTENV_EMPTY=from-file\nDOTENV_NEW=from-file\n", "DOTENV_NEW")
+	t.Setenv("DOTENV_SET", "from-env")
+	t.Setenv("DOTENV_EMPTY", "")
+	if err := whisper.LoadDotEnv(path); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"DOTENV_SET": "from-env", "DOTENV_EMPTY": "", "DOTENV_NEW": "from-file"} {
+		if got := os.Getenv(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestLoadDotEnvErrors(t *testing.T) {
+	for _, line := range []string{
+		"DOTENV_V",
+		"=value",
+		`DOTENV_V="unterminated`,
+		`DOTENV_V='unterminated`,
+	} {
+		path := writeDotEnv(t, "DOTENV_OK=1\n"+line+"\n", "DOTENV_OK", "DOTENV_V")
+		err := whisper.LoadDotEnv(path)
+		if err == nil || !strings.Contains(err.Error(), ":2:") {
+			t.Errorf("LoadDotEnv with %q = %v, want an error for line 2", line, err)
+		}
+	}
+	if err := whisper.LoadDotEnv(filepath.Join(t.TempDir(), "missing.env")); !os.IsNotExist(err) {
+		t.Errorf("LoadDotEnv of a missing file = %v, want a not-exist error", err)
+	}
+}
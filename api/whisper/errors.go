@@ -0,0 +1,39 @@
+package whisper
+
+import "errors"
+
+// Errors reported by the Client, for use with errors.Is. The returned errors
+// wrap them with additional context.
+var (
+	// ErrMissingAPIKey is returned when no API key is configured.
+	ErrMissingAPIKey = errors.New("missing API key")
+	// ErrMissingFilename is returned when the filename of the audio is not set.
+	ErrMissingFilename = errors.New("filename is not set")
+	// ErrEmptyFile is returned for audio without any data.
+	ErrEmptyFile = errors.New("audio is empty")
+	// ErrUnsupportedFormat is returned for audio in a format the API does not accept.
+	ErrUnsupportedFormat = errors.New("unsupported audio format")
+	// ErrFileTooLarge is returned for audio larger than the upload limit.
+	ErrFileTooLarge = errors.New("file too large")
+	// ErrPayloadTooLarge is returned when the server rejects the upload with
+	// 413 Payload Too Large, e.g. a gateway with a small body size limit.
+	ErrPayloadTooLarge = errors.New("payload too large")
+	// ErrTimeout matches a request that timed out in the HTTP client, the
+	// network, or after the duration set with WithTimeout. A canceled or
+	// expired context of the caller does not match it.
+	ErrTimeout = errors.New("request timed out")
+	// ErrEmptyResponse is returned for a successful response without a
+	// transcript, e.g. an empty body from a proxy. Use
+	// transcribe.WithAllowEmptyResult for audio that may be silent.
+	ErrEmptyResponse = errors.New("empty response")
+	// ErrResponseTooLarge is returned when a response body exceeds the configured maximum size.
+	ErrResponseTooLarge = errors.New("response body too large")
+)
+
+// Aliases of the errors above, under the names used by some callers.
+var (
+	// ErrNoFilename is ErrMissingFilename.
+	ErrNoFilename = ErrMissingFilename
+	// ErrNoAPIKey is ErrMissingAPIKey.
+	ErrNoAPIKey = ErrMissingAPIKey
+)
@@ -0,0 +1,151 @@
+package whisper_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	tests := []struct {
+		name     string
+		response whispertest.Response
+		client   []whisper.ClientOption
+		audio    []byte
+		opts     []transcribe.TranscribeOption
+		want     error
+	}{
+		{name: "missing API key", client: []whisper.ClientOption{whisper.WithKey("")}, want: whisper.ErrMissingAPIKey},
+		{name: "missing filename", opts: []transcribe.TranscribeOption{transcribe.WithFile("")}, want: whisper.ErrMissingFilename},
+		{name: "empty file", audio: []byte{}, want: whisper.ErrEmptyFile},
+		{
+			name:  "unsupported format",
+			audio: []byte("plain text"),
+			opts:  []transcribe.TranscribeOption{transcribe.WithFile("a.txt")},
+			want:  whisper.ErrUnsupportedFormat,
+		},
+		{name: "file too large", client: []whisper.ClientOption{whisper.WithMaxUploadSize(100)}, want: whisper.ErrFileTooLarge},
+		{
+			name:     "payload too large",
+			response: whispertest.Error(http.StatusRequestEntityTooLarge, "Request Entity Too Large"),
+			want:     whisper.ErrPayloadTooLarge,
+		},
+		{name: "empty response", response: jsonText(""), want: whisper.ErrEmptyResponse},
+		{
+			name:     "response too large",
+			response: jsonText(strings.Repeat("la ", 1000)),
+			client:   []whisper.ClientOption{whisper.WithMaxResponseSize(100)},
+			want:     whisper.ErrResponseTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := tt.response
+			if response.Body == "" {
+				response = jsonText("hello")
+			}
+			srv := whispertest.NewServer(response)
+			defer srv.Close()
+			c := whisper.NewClient(append([]whisper.ClientOption{whisper.WithKey("k"), whisper.WithBaseURL(srv.URL)}, tt.client...)...)
+
+			audio := tt.audio
+			if audio == nil {
+				audio = wavFile(1)
+			}
+			opts := append([]transcribe.TranscribeOption{transcribe.WithFile("a.wav")}, tt.opts...)
+			_, err := c.Transcribe(bytes.NewReader(audio), opts...)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Transcribe() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutErrors(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	const d = 50 * time.Millisecond
+	expired := func() (context.Context, context.CancelFunc) { return context.WithTimeout(context.Background(), d) }
+	canceled := func() (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(d, cancel)
+		return ctx, cancel
+	}
+	background := func() (context.Context, context.CancelFunc) { return context.Background(), func() {} }
+	tests := []struct {
+		name    string
+		client  []whisper.ClientOption
+		ctx     func() (context.Context, context.CancelFunc)
+		want    error
+		timeout bool
+	}{
+		{"WithTimeout", []whisper.ClientOption{whisper.WithTimeout(d)}, background, whisper.ErrTimeout, true},
+		{"HTTP client timeout", []whisper.ClientOption{whisper.WithHTTPClient(&http.Client{Timeout: d})}, background, whisper.ErrTimeout, true},
+		{"context deadline", nil, expired, context.DeadlineExceeded, false},
+		{"context canceled", nil, canceled, context.Canceled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := whisper.NewClient(append([]whisper.ClientOption{whisper.WithKey("k"), whisper.WithBaseURL(slow.URL)}, tt.client...)...)
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			_, err := c.TranscribeContext(ctx, bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Transcribe() = %v, want %v", err, tt.want)
+			}
+			if errors.Is(err, whisper.ErrTimeout) != tt.timeout {
+				t.Errorf("errors.Is(%v, ErrTimeout) = %v, want %v", err, !tt.timeout, tt.timeout)
+			}
+		})
+	}
+}
+
+func TestNoFilenameAndNoAPIKey(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)))
+	if !errors.Is(err, whisper.ErrNoFilename) {
+		t.Errorf("Transcribe() without a filename = %v, want ErrNoFilename", err)
+	}
+	if errors.Is(err, whisper.ErrNoAPIKey) {
+		t.Errorf("Transcribe() without a filename = %v, matches ErrNoAPIKey", err)
+	}
+
+	c = whisper.NewClient(whisper.WithBaseURL(srv.URL))
+	_, err = c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if !errors.Is(err, whisper.ErrNoAPIKey) {
+		t.Errorf("Transcribe() without a key = %v, want ErrNoAPIKey", err)
+	}
+	if errors.Is(err, whisper.ErrNoFilename) {
+		t.Errorf("Transcribe() without a key = %v, matches ErrNoFilename", err)
+	}
+	if !strings.Contains(err.Error(), "OPENAI_API_KEY") {
+		t.Errorf("Transcribe() without a key = %v, want it to name OPENAI_API_KEY", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("server got %d requests, want none", n)
+	}
+}
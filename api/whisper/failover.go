@@ -0,0 +1,48 @@
+package whisper
+
+import "errors"
+
+// Endpoint is an API base URL with an optional key of its own. An empty key
+// means the Client's key is used.
+type Endpoint struct {
+	BaseURL string
+	APIKey  string
+}
+
+// name returns the base URL the endpoint resolves to.
+func (ep Endpoint) name() string {
+	if ep.BaseURL == "" {
+		return DefaultBase
+	}
+	return ep.BaseURL
+}
+
+// WithFallbackBaseURLs sets endpoints that a transcription is sent to, in
+// order, when the previous one fails with a connection error or a 5xx
+// response. They use the Client's API key.
+func WithFallbackBaseURLs(urls ...string) ClientOption {
+	return func(c *Client) {
+		for _, u := range urls {
+			c.fallbacks = append(c.fallbacks, Endpoint{BaseURL: u})
+		}
+	}
+}
+
+// WithFallbackEndpoints is like WithFallbackBaseURLs but allows each endpoint
+// to have its own API key.
+func WithFallbackEndpoints(endpoints ...Endpoint) ClientOption {
+	return func(c *Client) {
+		c.fallbacks = append(c.fallbacks, endpoints...)
+	}
+}
+
+// failover reports whether a request that failed with err should be sent to
+// the next endpoint: connection errors and 5xx responses are, rate limits
+// and client errors are not.
+func failover(retryable bool, err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return retryable
+}
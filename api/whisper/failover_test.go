@@ -0,0 +1,115 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// closedURL returns the URL of a server that no longer accepts connections.
+func closedURL() string {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	return srv.URL
+}
+
+func TestFailover(t *testing.T) {
+	tests := []struct {
+		name     string
+		primary  whispertest.Response
+		down     bool
+		failover bool
+	}{
+		{name: "server error", primary: whispertest.Error(http.StatusServiceUnavailable, "overloaded"), failover: true},
+		{name: "connection error", down: true, failover: true},
+		{name: "client error", primary: whispertest.Error(http.StatusBadRequest, "invalid file")},
+		{name: "rate limited", primary: whispertest.RateLimited(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := whispertest.NewServer(tt.primary)
+			defer primary.Close()
+			base := primary.URL
+			if tt.down {
+				base = closedURL()
+			}
+			fallback := whispertest.NewServer(jsonText("from fallback"))
+			defer fallback.Close()
+			c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(base), whisper.WithFallbackBaseURLs(fallback.URL))
+
+			tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+			if !tt.failover {
+				if err == nil {
+					t.Fatalf("Transcribe() = %q, want the primary's error", tr.Text)
+				}
+				if n := len(fallback.Requests()); n != 0 {
+					t.Errorf("fallback received %d requests, want none", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tr.Text != "from fallback" || tr.Meta.Endpoint != fallback.URL {
+				t.Errorf("Text, Meta.Endpoint = %q, %q; want the fallback's", tr.Text, tr.Meta.Endpoint)
+			}
+		})
+	}
+}
+
+func TestFailoverEndpointKeys(t *testing.T) {
+	primary := whispertest.NewServer(whispertest.Error(http.StatusBadGateway, "bad gateway"))
+	defer primary.Close()
+	second := whispertest.NewServer(whispertest.Error(http.StatusInternalServerError, "error"))
+	defer second.Close()
+	third := whispertest.NewServer(jsonText("hello"))
+	defer third.Close()
+	c := whisper.NewClient(whisper.WithKey("primary-key"), whisper.WithBaseURL(primary.URL),
+		whisper.WithFallbackEndpoints(whisper.Endpoint{BaseURL: second.URL}, whisper.Endpoint{BaseURL: third.URL, APIKey: "third-key"}))
+
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Meta.Endpoint != third.URL {
+		t.Errorf("Meta.Endpoint = %q, want %q", tr.Meta.Endpoint, third.URL)
+	}
+	for _, tt := range []struct {
+		srv  *whispertest.Server
+		auth string
+	}{{primary, "Bearer primary-key"}, {second, "Bearer primary-key"}, {third, "Bearer third-key"}} {
+		reqs := tt.srv.Requests()
+		if len(reqs) != 1 || reqs[0].Header.Get("Authorization") != tt.auth {
+			t.Errorf("%s received %d requests, want one with %q", tt.srv.URL, len(reqs), tt.auth)
+		}
+	}
+}
+
+func TestFailoverAllFailed(t *testing.T) {
+	primary := whispertest.NewServer(whispertest.Error(http.StatusServiceUnavailable, "overloaded"))
+	defer primary.Close()
+	down := closedURL()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(primary.URL), whisper.WithFallbackBaseURLs(down))
+
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var apiErr *whisper.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("errors.As(%v) does not reach the primary's 503", err)
+	}
+	var tErr *whisper.TransportError
+	if !errors.As(err, &tErr) {
+		t.Errorf("errors.As(%v) does not reach the fallback's connection error", err)
+	}
+	for _, endpoint := range []string{primary.URL + ": ", down + ": "} {
+		if err == nil || !strings.Contains(err.Error(), endpoint) {
+			t.Errorf("error %v does not name endpoint %s", err, endpoint)
+		}
+	}
+}
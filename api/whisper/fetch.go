@@ -0,0 +1,106 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// ErrAudioFetch is returned when the audio passed to TranscribeURL cannot be downloaded.
+var ErrAudioFetch = errors.New("fetching audio failed")
+
+// audioExtensions maps the content types audio is commonly served with to
+// the extension of the filename sent to the API.
+var audioExtensions = map[string]string{
+	"application/ogg": ".ogg",
+	"audio/flac":      ".flac",
+	"audio/m4a":       ".m4a",
+	"audio/mp3":       ".mp3",
+	"audio/mp4":       ".m4a",
+	"audio/mpeg":      ".mp3",
+	"audio/ogg":       ".ogg",
+	"audio/vnd.wave":  ".wav",
+	"audio/wav":       ".wav",
+	"audio/wave":      ".wav",
+	"audio/webm":      ".webm",
+	"audio/x-flac":    ".flac",
+	"audio/x-m4a":     ".m4a",
+	"audio/x-wav":     ".wav",
+	"video/mp4":       ".mp4",
+	"video/webm":      ".webm",
+}
+
+// TranscribeURL downloads the audio at audioURL and streams it into a
+// transcription request. The filename sent to the API is taken from the URL
+// path unless set with transcribe.WithFile; if it lacks an audio extension,
+// one is derived from the Content-Type of the download.
+func (c *Client) TranscribeURL(ctx context.Context, audioURL string, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	if err := c.ready(); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(audioURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAudioFetch, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAudioFetch, err)
+	}
+
+	// The download uses the HTTP client without the middlewares, which are
+	// meant for the API, so that proxy, TLS and timeout settings apply but
+	// no credentials are sent to other hosts.
+	resp, err := c.baseHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAudioFetch, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected response: %s", ErrAudioFetch, resp.Status)
+	}
+
+	name := fetchedFilename(u, resp.Header.Get("Content-Type"))
+	opts = append([]transcribe.TranscribeOption{transcribe.WithFile(name)}, opts...)
+	return c.TranscribeContext(ctx, fetchReader{resp.Body}, opts...)
+}
+
+// fetchedFilename returns the filename of audio downloaded from u with the
+// given Content-Type.
+func fetchedFilename(u *url.URL, contentType string) string {
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = "audio"
+	}
+	ext := path.Ext(name)
+	if _, ok := audioContentTypes[strings.ToLower(ext)]; ok {
+		return name
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if typeExt, ok := audioExtensions[mediaType]; ok {
+			return strings.TrimSuffix(name, ext) + typeExt
+		}
+	}
+	return name
+}
+
+// fetchReader wraps errors reading a download in ErrAudioFetch.
+type fetchReader struct {
+	r io.Reader
+}
+
+func (f fetchReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("%w: %w", ErrAudioFetch, err)
+	}
+	return n, err
+}
@@ -0,0 +1,144 @@
+package whisper_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// audioServer serves wavFile(1) with the Content-Type given in the ct query
+// parameter.
+func audioServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", r.URL.Query().Get("ct"))
+		w.Write(wavFile(1))
+	}))
+}
+
+func TestTranscribeURLFilename(t *testing.T) {
+	api := whispertest.NewServer(jsonText("hello"))
+	defer api.Close()
+	audio := audioServer()
+	defer audio.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(api.URL))
+
+	tests := []struct {
+		path, contentType string
+		opts              []transcribe.TranscribeOption
+		want              string
+	}{
+		{"/episodes/42.wav", "application/octet-stream", nil, "42.wav"},
+		{"/download", "audio/wav", nil, "download.wav"},
+		{"/download", "audio/x-wav; charset=binary", nil, "download.wav"},
+		{"/stream.php", "audio/wav", nil, "stream.wav"},
+		{"/", "audio/wav", nil, "audio.wav"},
+		{"/episodes/42.WAV", "audio/mpeg", nil, "42.WAV"},
+		{"/download", "audio/wav", []transcribe.TranscribeOption{transcribe.WithFile("talk.wav")}, "talk.wav"},
+	}
+	for _, tt := range tests {
+		audioURL := audio.URL + tt.path + "?ct=" + url.QueryEscape(tt.contentType)
+		if _, err := c.TranscribeURL(context.Background(), audioURL, tt.opts...); err != nil {
+			t.Errorf("TranscribeURL(%s): %v", audioURL, err)
+			continue
+		}
+		reqs := api.Requests()
+		req := reqs[len(reqs)-1]
+		if req.File != tt.want {
+			t.Errorf("TranscribeURL(%s) uploaded %q, want %q", audioURL, req.File, tt.want)
+		}
+		if !bytes.Equal(req.Audio, wavFile(1)) {
+			t.Errorf("TranscribeURL(%s) uploaded %d bytes, want the downloaded audio", audioURL, len(req.Audio))
+		}
+	}
+}
+
+func TestTranscribeURLSkipsMiddlewares(t *testing.T) {
+	api := whispertest.NewServer(jsonText("hello"))
+	defer api.Close()
+	audio := audioServer()
+	defer audio.Close()
+
+	var tc tracer
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(api.URL), whisper.WithRoundTripper(tc.middleware("a")))
+	if _, err := c.TranscribeURL(context.Background(), audio.URL+"/a.wav"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tc.take(), []string{"a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requests went through %q, want only the API request through the middleware", got)
+	}
+}
+
+func TestTranscribeURLUsesHTTPClient(t *testing.T) {
+	api := whispertest.NewServer(jsonText("hello"))
+	defer api.Close()
+	var auth []string
+	audio := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Write(wavFile(1))
+	}))
+	defer audio.Close()
+
+	var tc tracer
+	proxied := &http.Transport{Proxy: func(*http.Request) (*url.URL, error) {
+		tc.record("transport")
+		return nil, nil
+	}}
+	clients := map[string]*whisper.Client{
+		"base": whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(api.URL),
+			whisper.WithHTTPClient(tc.transport("base")), whisper.WithRoundTripper(tc.middleware("mw"))),
+		"transport": whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(api.URL),
+			whisper.WithTransport(proxied), whisper.WithRoundTripper(tc.middleware("mw"))),
+	}
+	for name, c := range clients {
+		if _, err := c.TranscribeURL(context.Background(), audio.URL+"/a.wav"); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := tc.take(), []string{name, "mw", name}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: requests went through %q, want %q", name, got, want)
+		}
+	}
+	if !reflect.DeepEqual(auth, []string{"", ""}) {
+		t.Errorf("audio downloads sent Authorization %q, want none", auth)
+	}
+}
+
+func TestTranscribeURLErrors(t *testing.T) {
+	api := whispertest.NewServer(jsonText("hello"))
+	defer api.Close()
+	audio := audioServer()
+	defer audio.Close()
+	truncated := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100000")
+		w.Write(wavFile(1))
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}))
+	defer truncated.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(api.URL))
+
+	for _, audioURL := range []string{
+		audio.URL + "/missing",
+		"http://[::1]:namedport/a.wav",
+		truncated.URL + "/a.wav",
+	} {
+		if _, err := c.TranscribeURL(context.Background(), audioURL); !errors.Is(err, whisper.ErrAudioFetch) {
+			t.Errorf("TranscribeURL(%s) = %v, want ErrAudioFetch", audioURL, err)
+		}
+	}
+	if n := len(api.Requests()); n != 0 {
+		t.Errorf("API received %d requests, want none", n)
+	}
+}
@@ -0,0 +1,41 @@
+package whisper
+
+import (
+	"context"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// TranscribeFiles transcribes the files one after another with the same
+// options. The responses are in the order of files, with nil for files that
+// failed; the error is then a *BatchError.
+func (c *Client) TranscribeFiles(files []string, opts ...transcribe.TranscribeOption) ([]*models.TranscribeResponse, error) {
+	return c.TranscribeFilesContext(context.Background(), files, opts...)
+}
+
+// TranscribeFilesContext is like TranscribeFiles but carries a context for
+// the requests. Once ctx is done, the remaining files are not sent and fail
+// with the context's error.
+func (c *Client) TranscribeFilesContext(ctx context.Context, files []string, opts ...transcribe.TranscribeOption) ([]*models.TranscribeResponse, error) {
+	responses := make([]*models.TranscribeResponse, len(files))
+	batchErr := &BatchError{Total: len(files)}
+	for i, file := range files {
+		if err := ctx.Err(); err != nil {
+			for j := i; j < len(files); j++ {
+				batchErr.Errors = append(batchErr.Errors, &BatchItemError{Index: j, Input: files[j], Err: err})
+			}
+			break
+		}
+		tr, err := c.TranscribeFileContext(ctx, file, opts...)
+		if err != nil {
+			batchErr.Errors = append(batchErr.Errors, &BatchItemError{Index: i, Input: file, Err: err})
+			continue
+		}
+		responses[i] = tr
+	}
+	if len(batchErr.Errors) > 0 {
+		return responses, batchErr
+	}
+	return responses, nil
+}
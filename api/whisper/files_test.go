@@ -0,0 +1,94 @@
+package whisper_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+)
+
+// wavFiles writes a one-second WAV file for each name and returns their paths.
+func wavFiles(t *testing.T, names ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name)
+		if err := os.WriteFile(paths[i], wavFile(1), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func TestTranscribeFilesOrder(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("one"), whispertest.Error(http.StatusBadRequest, "invalid file"), jsonText("three"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+	files := wavFiles(t, "1.wav", "2.wav", "3.wav")
+
+	trs, err := c.TranscribeFiles(files)
+	var batchErr *whisper.BatchError
+	if !errors.As(err, &batchErr) || !reflect.DeepEqual(batchErr.Failed(), []int{1}) {
+		t.Fatalf("TranscribeFiles() = %v, want only input 1 to fail", err)
+	}
+	if len(trs) != 3 || trs[0].Text != "one" || trs[1] != nil || trs[2].Text != "three" {
+		t.Errorf("responses = %v, want one, nil and three", trs)
+	}
+	var got []string
+	for _, req := range srv.Requests() {
+		got = append(got, req.File)
+	}
+	if want := []string{"1.wav", "2.wav", "3.wav"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("uploaded %v, want %v", got, want)
+	}
+
+	if trs, err := c.TranscribeFiles(nil); err != nil || len(trs) != 0 {
+		t.Errorf("TranscribeFiles(nil) = %v, %v; want no responses", trs, err)
+	}
+}
+
+func TestTranscribeFilesCanceled(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The first response is read and then cancels the batch.
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithRoundTripper(func(next http.RoundTripper) http.RoundTripper {
+		return whisper.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			defer cancel()
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, err
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			return resp, err
+		})
+	}))
+	files := wavFiles(t, "1.wav", "2.wav", "3.wav")
+
+	trs, err := c.TranscribeFilesContext(ctx, files)
+	var batchErr *whisper.BatchError
+	if !errors.As(err, &batchErr) || !reflect.DeepEqual(batchErr.Failed(), []int{1, 2}) {
+		t.Fatalf("TranscribeFilesContext() = %v, want inputs 1 and 2 to fail", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error %v does not match context.Canceled", err)
+	}
+	if trs[0] == nil || trs[0].Text != "hello" {
+		t.Errorf("first response = %v, want the transcript", trs[0])
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("server received %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,33 @@
+package whisper
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// WithAutoDowngradeFormat makes a request that a backend rejects because it
+// does not support the response format, such as a minimal server without
+// verbose_json, be sent once more with the json format. The response then
+// only has its Text set.
+func WithAutoDowngradeFormat() ClientOption {
+	return func(c *Client) {
+		c.autoDowngrade = true
+	}
+}
+
+// unsupportedFormat reports whether err is a 400 response rejecting the
+// response_format. It only matches on the error message, so other bad
+// requests are not retried.
+func unsupportedFormat(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	if apiErr.Param == "response_format" {
+		return true
+	}
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "response_format") &&
+		(strings.Contains(msg, "unsupported") || strings.Contains(msg, "not supported") || strings.Contains(msg, "invalid"))
+}
@@ -0,0 +1,59 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestAutoDowngradeFormat(t *testing.T) {
+	rejected := whispertest.Error(http.StatusBadRequest, "response_format 'verbose_json' is not supported by this server")
+	unrelated := whispertest.Error(http.StatusBadRequest, "Invalid file format.")
+	tests := []struct {
+		name      string
+		downgrade bool
+		responses []whispertest.Response
+		formats   []string
+		ok        bool
+	}{
+		{"downgraded", true, []whispertest.Response{rejected, jsonText("hello")}, []string{"verbose_json", "json"}, true},
+		{"downgraded once", true, []whispertest.Response{rejected}, []string{"verbose_json", "json"}, false},
+		{"not enabled", false, []whispertest.Response{rejected, jsonText("hello")}, []string{"verbose_json"}, false},
+		{"unrelated 400", true, []whispertest.Response{unrelated, jsonText("hello")}, []string{"verbose_json"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := whispertest.NewServer(tt.responses...)
+			defer srv.Close()
+			opts := []whisper.ClientOption{whisper.WithKey("k"), whisper.WithBaseURL(srv.URL)}
+			if tt.downgrade {
+				opts = append(opts, whisper.WithAutoDowngradeFormat())
+			}
+			c := whisper.NewClient(opts...)
+
+			tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithResponseFormat("verbose_json"))
+			var apiErr *whisper.APIError
+			switch {
+			case tt.ok && err != nil:
+				t.Fatal(err)
+			case tt.ok && (tr.Text != "hello" || tr.Segments != nil):
+				t.Errorf("downgraded response = %+v, want only Text", tr)
+			case !tt.ok && (!errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest):
+				t.Errorf("Transcribe() = %v, want the 400 *APIError", err)
+			}
+			var formats []string
+			for _, req := range srv.Requests() {
+				formats = append(formats, req.Fields["response_format"]...)
+			}
+			if !slices.Equal(formats, tt.formats) {
+				t.Errorf("requested formats %q, want %q", formats, tt.formats)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package whisper
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// audioContentTypes maps the audio file extensions accepted by the API to the
+// content type declared for the uploaded file part.
+var audioContentTypes = map[string]string{
+	".flac": "audio/flac",
+	".m4a":  "audio/mp4",
+	".mp3":  "audio/mpeg",
+	".mp4":  "audio/mp4",
+	".mpeg": "audio/mpeg",
+	".mpga": "audio/mpeg",
+	".oga":  "audio/ogg",
+	".ogg":  "audio/ogg",
+	".wav":  "audio/wav",
+	".webm": "audio/webm",
+}
+
+// contentTypeFor returns the content type for the given filename based on its
+// extension, falling back to application/octet-stream.
+func contentTypeFor(filename string) string {
+	if ct, ok := audioContentTypes[strings.ToLower(filepath.Ext(filename))]; ok {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// sniffLen is the number of leading bytes sniffFormat looks at.
+const sniffLen = 12
+
+// sniffFormat detects the audio container from the leading bytes of a file
+// and returns its usual extension, or "" if the content is not recognized.
+// QuickTime movies are reported as ".mov", which the API does not accept.
+func sniffFormat(head []byte) string {
+	switch {
+	case len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == "WAVE":
+		return ".wav"
+	case bytes.HasPrefix(head, []byte("ID3")), len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0:
+		return ".mp3"
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		switch string(head[8:12]) {
+		case "qt  ":
+			return ".mov"
+		case "M4A ", "M4B ":
+			return ".m4a"
+		}
+		return ".mp4"
+	case bytes.HasPrefix(head, []byte("OggS")):
+		return ".ogg"
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return ".flac"
+	case bytes.HasPrefix(head, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return ".webm"
+	}
+	return ""
+}
+
+// sameContainer reports whether two extensions denote the same container.
+func sameContainer(a, b string) bool {
+	family := func(ext string) string {
+		switch ext {
+		case ".mpeg", ".mpga":
+			return ".mp3"
+		case ".m4a":
+			return ".mp4"
+		case ".oga":
+			return ".ogg"
+		}
+		return ext
+	}
+	return family(a) == family(b)
+}
+
+// checkFormat validates the audio against the formats the API accepts, using
+// both the extension of filename and the leading bytes head. If the content
+// is recognized but does not match the extension, the filename is returned
+// with the extension of the detected format.
+func checkFormat(filename string, head []byte) (string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	detected := sniffFormat(head)
+	switch {
+	case detected == "":
+		// Leave unrecognized content to the API as long as the name is valid.
+		if _, ok := audioContentTypes[ext]; !ok {
+			return "", fmt.Errorf("%s: %w (extension %q)", filename, ErrUnsupportedFormat, ext)
+		}
+		return filename, nil
+	case audioContentTypes[detected] == "":
+		return "", fmt.Errorf("%s: %w (detected %s content)", filename, ErrUnsupportedFormat, strings.TrimPrefix(detected, "."))
+	case sameContainer(ext, detected):
+		return filename, nil
+	}
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + detected, nil
+}
@@ -0,0 +1,74 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// audioHeads are leading bytes of audio in each container the API accepts.
+var audioHeads = map[string][]byte{
+	"flac": []byte("fLaC\x00\x00\x00\x22"),
+	"m4a":  []byte("\x00\x00\x00\x20ftypM4A \x00\x00\x00\x00"),
+	"mp3":  []byte("ID3\x04\x00\x00\x00\x00\x00\x00"),
+	"mp4":  []byte("\x00\x00\x00\x20ftypisom\x00\x00\x02\x00"),
+	"ogg":  []byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00"),
+	"wav":  wavFile(1)[:44],
+	"webm": []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\xf7\x81"),
+}
+
+func audioOf(container string) []byte {
+	return append(append([]byte(nil), audioHeads[container]...), make([]byte, 256)...)
+}
+
+func TestUploadContentType(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	tests := []struct {
+		file, container, contentType string
+	}{
+		{"a.flac", "flac", "audio/flac"},
+		{"a.m4a", "m4a", "audio/mp4"},
+		{"a.mp3", "mp3", "audio/mpeg"},
+		{"a.mp4", "mp4", "audio/mp4"},
+		{"a.mpeg", "mp3", "audio/mpeg"},
+		{"a.mpga", "mp3", "audio/mpeg"},
+		{"a.oga", "ogg", "audio/ogg"},
+		{"a.ogg", "ogg", "audio/ogg"},
+		{"a.wav", "wav", "audio/wav"},
+		{"a.webm", "webm", "audio/webm"},
+		{"A.MP3", "mp3", "audio/mpeg"},
+	}
+	for _, tt := range tests {
+		if _, err := c.Transcribe(bytes.NewReader(audioOf(tt.container)), transcribe.WithFile(tt.file)); err != nil {
+			t.Errorf("Transcribe(%s): %v", tt.file, err)
+			continue
+		}
+		reqs := srv.Requests()
+		if got := reqs[len(reqs)-1].ContentType; got != tt.contentType {
+			t.Errorf("%s uploaded as %q, want %q", tt.file, got, tt.contentType)
+		}
+	}
+}
+
+func TestUploadUnsupportedFormat(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	for _, file := range []string{"a.txt", "a.mov", "noext"} {
+		_, err := c.Transcribe(bytes.NewReader([]byte("plain text, not audio")), transcribe.WithFile(file))
+		if !errors.Is(err, whisper.ErrUnsupportedFormat) {
+			t.Errorf("Transcribe(%s) = %v, want ErrUnsupportedFormat", file, err)
+		}
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("server received %d requests, want none", n)
+	}
+}
@@ -0,0 +1,113 @@
+package whisper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidLanguage is returned for a language Whisper does not support.
+var ErrInvalidLanguage = errors.New("invalid language")
+
+// languages maps the codes of the languages Whisper supports to their names.
+var languages = map[string]string{
+	"af": "afrikaans", "am": "amharic", "ar": "arabic", "as": "assamese", "az": "azerbaijani",
+	"ba": "bashkir", "be": "belarusian", "bg": "bulgarian", "bn": "bengali", "bo": "tibetan",
+	"br": "breton", "bs": "bosnian", "ca": "catalan", "cs": "czech", "cy": "welsh",
+	"da": "danish", "de": "german", "el": "greek", "en": "english", "es": "spanish",
+	"et": "estonian", "eu": "basque", "fa": "persian", "fi": "finnish", "fo": "faroese",
+	"fr": "french", "gl": "galician", "gu": "gujarati", "ha": "hausa", "haw": "hawaiian",
+	"he": "hebrew", "hi": "hindi", "hr": "croatian", "ht": "haitian creole", "hu": "hungarian",
+	"hy": "armenian", "id": "indonesian", "is": "icelandic", "it": "italian", "ja": "japanese",
+	"jw": "javanese", "ka": "georgian", "kk": "kazakh", "km": "khmer", "kn": "kannada",
+	"ko": "korean", "la": "latin", "lb": "luxembourgish", "ln": "lingala", "lo": "lao",
+	"lt": "lithuanian", "lv": "latvian", "mg": "malagasy", "mi": "maori", "mk": "macedonian",
+	"ml": "malayalam", "mn": "mongolian", "mr": "marathi", "ms": "malay", "mt": "maltese",
+	"my": "myanmar", "ne": "nepali", "nl": "dutch", "nn": "nynorsk", "no": "norwegian",
+	"oc": "occitan", "pa": "punjabi", "pl": "polish", "ps": "pashto", "pt": "portuguese",
+	"ro": "romanian", "ru": "russian", "sa": "sanskrit", "sd": "sindhi", "si": "sinhala",
+	"sk": "slovak", "sl": "slovenian", "sn": "shona", "so": "somali", "sq": "albanian",
+	"sr": "serbian", "su": "sundanese", "sv": "swedish", "sw": "swahili", "ta": "tamil",
+	"te": "telugu", "tg": "tajik", "th": "thai", "tk": "turkmen", "tl": "tagalog",
+	"tr": "turkish", "tt": "tatar", "uk": "ukrainian", "ur": "urdu", "uz": "uzbek",
+	"vi": "vietnamese", "yi": "yiddish", "yo": "yoruba", "yue": "cantonese", "zh": "chinese",
+}
+
+// languageAliases maps alternative language names to their codes.
+var languageAliases = map[string]string{
+	"burmese": "my", "castilian": "es", "flemish": "nl", "haitian": "ht",
+	"letzeburgesch": "lb", "mandarin": "zh", "moldavian": "ro", "moldovan": "ro",
+	"panjabi": "pa", "pushto": "ps", "sinhalese": "si", "valencian": "ca",
+}
+
+// languageNames maps language names and aliases to their codes.
+var languageNames = func() map[string]string {
+	names := make(map[string]string, len(languages)+len(languageAliases))
+	for code, name := range languages {
+		names[name] = code
+	}
+	for name, code := range languageAliases {
+		names[name] = code
+	}
+	return names
+}()
+
+// normalizeLanguage returns the code of the language given as a code or an
+// English name, in any case. For unknown languages it returns an error that
+// suggests the closest valid code.
+func normalizeLanguage(lang string) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(lang))
+	if _, ok := languages[key]; ok {
+		return key, nil
+	}
+	if code, ok := languageNames[key]; ok {
+		return code, nil
+	}
+	return "", fmt.Errorf("%w %q (did you mean %q?)", ErrInvalidLanguage, lang, suggestLanguage(key))
+}
+
+// suggestLanguage returns the code of the language closest to key: a code
+// that key starts with, a name that starts with key, or else the code or
+// name with the smallest edit distance.
+func suggestLanguage(key string) string {
+	best, bestDist := "", -1
+	consider := func(candidate, code string) {
+		d := levenshtein(key, candidate)
+		if bestDist < 0 || d < bestDist || (d == bestDist && code < best) {
+			best, bestDist = code, d
+		}
+	}
+	for code, name := range languages {
+		if strings.HasPrefix(key, code) || strings.HasPrefix(name, key) {
+			consider(key, code)
+		}
+		consider(code, code)
+		consider(name, code)
+	}
+	for name, code := range languageAliases {
+		consider(name, code)
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,66 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestLanguageNormalization(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hola"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	tests := []struct{ lang, want string }{
+		{"es", "es"},
+		{"ES", "es"},
+		{" es ", "es"},
+		{"Spanish", "es"},
+		{"spanish", "es"},
+		{"Castilian", "es"},
+		{"haitian creole", "ht"},
+		{"yue", "yue"},
+	}
+	for _, tt := range tests {
+		if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithLanguage(tt.lang)); err != nil {
+			t.Errorf("language %q: %v", tt.lang, err)
+			continue
+		}
+		reqs := srv.Requests()
+		if got := reqs[len(reqs)-1].Fields["language"]; len(got) != 1 || got[0] != tt.want {
+			t.Errorf("language %q was sent as %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestInvalidLanguage(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hola"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	tests := []struct{ lang, suggestion string }{
+		{"esp", "es"},
+		{"Spansh", "es"},
+		{"englsh", "en"},
+		{"deu", "de"},
+		{"xx", ""},
+	}
+	for _, tt := range tests {
+		_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithLanguage(tt.lang))
+		if !errors.Is(err, whisper.ErrInvalidLanguage) {
+			t.Errorf("language %q: %v, want ErrInvalidLanguage", tt.lang, err)
+			continue
+		}
+		if tt.suggestion != "" && !strings.Contains(err.Error(), `did you mean "`+tt.suggestion+`"?`) {
+			t.Errorf("language %q: %v, want a suggestion of %q", tt.lang, err, tt.suggestion)
+		}
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("server received %d requests, want none", n)
+	}
+}
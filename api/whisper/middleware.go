@@ -0,0 +1,48 @@
+package whisper
+
+import "net/http"
+
+// RoundTripperFunc adapts a function to an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithRoundTripper wraps the transport of the Client's HTTP client with mw.
+// Middlewares run in the order they are added, the first one outermost. An
+// HTTP client set with WithHTTPClient is copied, not modified.
+func WithRoundTripper(mw func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, mw)
+	}
+}
+
+// RequestIDMiddleware is an example middleware that sets an X-Request-ID
+// header on requests that do not carry one.
+func RequestIDMiddleware(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("X-Request-ID") == "" {
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Request-ID", newUUID())
+		}
+		return next.RoundTrip(req)
+	})
+}
+
+// wrapTransport returns a copy of hc whose transport is wrapped with mws.
+func wrapTransport(hc *http.Client, mws []func(http.RoundTripper) http.RoundTripper) *http.Client {
+	if len(mws) == 0 {
+		return hc
+	}
+	wrapped := *hc
+	rt := wrapped.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	for i := len(mws) - 1; i >= 0; i-- {
+		rt = mws[i](rt)
+	}
+	wrapped.Transport = rt
+	return &wrapped
+}
@@ -0,0 +1,64 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// pingTimeout bounds a Ping when the context carries no deadline.
+const pingTimeout = 10 * time.Second
+
+// ErrUnauthorized matches an APIError for a 401 response, i.e. the API
+// rejected the configured credentials.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Ping checks that the API is reachable and the credentials are accepted by
+// listing the available models. A rejected key yields an *APIError matching
+// ErrUnauthorized. An unreachable endpoint or a 5xx response yields a
+// *TransportError, which for a 5xx wraps the *APIError. All are wrapped.
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.ready(); err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pingTimeout)
+		defer cancel()
+	}
+
+	apiKey, err := c.resolveAPIKey(ctx, "")
+	if err != nil {
+		return err
+	}
+	path := "models"
+	if c.azureEndpoint != "" {
+		path = c.azureEndpoint + "/openai/models"
+	}
+	var prog progress
+	req, err := c.newRequest(withProgress(ctx, &prog), http.MethodGet, c.URL(path), nil, apiKey)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	resp, err := c.do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		return fmt.Errorf("ping: %w", c.transportError(ctx, &prog, req, err, elapsed, "", -1))
+	}
+	defer resp.Body.Close()
+	c.updateRateLimit(resp.Header)
+
+	if resp.StatusCode != http.StatusOK {
+		err := responseError(resp, resp.Body, elapsed)
+		if resp.StatusCode >= 500 {
+			// Like a network failure, a server error means the API is unavailable.
+			err = c.transportError(ctx, &prog, req, err, elapsed, "", -1)
+		}
+		return fmt.Errorf("ping: %w", err)
+	}
+	return nil
+}
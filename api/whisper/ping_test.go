@@ -0,0 +1,94 @@
+package whisper_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+)
+
+func TestPing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/models" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer sk-good" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"Incorrect API key provided.","type":"invalid_request_error","code":"invalid_api_key"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","data":[{"id":"whisper-1","object":"model"}]}`))
+	}))
+	defer srv.Close()
+
+	c := whisper.NewClient(whisper.WithKey("sk-good"), whisper.WithBaseURL(srv.URL))
+	if err := c.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() = %v, want nil", err)
+	}
+
+	c = whisper.NewClient(whisper.WithKey("sk-bad"), whisper.WithBaseURL(srv.URL))
+	err := c.Ping(context.Background())
+	var apiErr *whisper.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Ping() with a bad key = %v, want a 401 *APIError", err)
+	}
+	if !errors.Is(err, whisper.ErrUnauthorized) {
+		t.Errorf("Ping() with a bad key = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestPingConnectionRefused(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+	err := c.Ping(context.Background())
+	var tErr *whisper.TransportError
+	if !errors.As(err, &tErr) {
+		t.Errorf("Ping() = %v, want a *TransportError", err)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Errorf("Ping() = %v, want ECONNREFUSED", err)
+	}
+	if errors.Is(err, whisper.ErrUnauthorized) {
+		t.Errorf("Ping() = %v matches ErrUnauthorized", err)
+	}
+}
+
+func TestPingServerError(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error":{"message":"The engine is currently overloaded.","type":"server_error"}}`))
+	}))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	err := c.Ping(context.Background())
+	var tErr *whisper.TransportError
+	if !errors.As(err, &tErr) || tErr.Phase != whisper.PhaseResponse {
+		t.Errorf("Ping() = %v, want a *TransportError in the response phase", err)
+	}
+	var apiErr *whisper.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Ping() = %v, want it to wrap the 503 *APIError", err)
+	}
+	if errors.Is(err, whisper.ErrUnauthorized) {
+		t.Errorf("Ping() = %v matches ErrUnauthorized", err)
+	}
+
+	// Other client errors are returned as they are.
+	status = http.StatusNotFound
+	err = c.Ping(context.Background())
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || errors.As(err, &tErr) {
+		t.Errorf("Ping() = %v, want a bare 404 *APIError", err)
+	}
+}
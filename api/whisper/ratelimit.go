@@ -0,0 +1,82 @@
+package whisper
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the quota reported by the API in the x-ratelimit-* response headers.
+type RateLimit struct {
+	LimitRequests     int
+	RemainingRequests int
+	ResetRequests     time.Duration
+	LimitTokens       int
+	RemainingTokens   int
+	ResetTokens       time.Duration
+}
+
+// rateLimitState holds the most recent RateLimit seen by a Client.
+type rateLimitState struct {
+	mu   sync.Mutex
+	last *RateLimit
+}
+
+// parseRateLimit reads the x-ratelimit-* headers. It returns nil if none are present.
+func parseRateLimit(h http.Header) *RateLimit {
+	var rl RateLimit
+	found := false
+	parseInt := func(name string, dst *int) {
+		if v := h.Get(name); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				*dst = n
+				found = true
+			}
+		}
+	}
+	parseDuration := func(name string, dst *time.Duration) {
+		if v := h.Get(name); v != "" {
+			if d, err := time.ParseDuration(v); err == nil {
+				*dst = d
+				found = true
+			}
+		}
+	}
+	parseInt("x-ratelimit-limit-requests", &rl.LimitRequests)
+	parseInt("x-ratelimit-remaining-requests", &rl.RemainingRequests)
+	parseDuration("x-ratelimit-reset-requests", &rl.ResetRequests)
+	parseInt("x-ratelimit-limit-tokens", &rl.LimitTokens)
+	parseInt("x-ratelimit-remaining-tokens", &rl.RemainingTokens)
+	parseDuration("x-ratelimit-reset-tokens", &rl.ResetTokens)
+	if !found {
+		return nil
+	}
+	return &rl
+}
+
+// updateRateLimit records the rate limit reported in the response headers, if any.
+func (c *Client) updateRateLimit(h http.Header) {
+	rl := parseRateLimit(h)
+	if rl == nil {
+		return
+	}
+	c.rateLimit.mu.Lock()
+	c.rateLimit.last = rl
+	c.rateLimit.mu.Unlock()
+}
+
+// LastRateLimit returns the rate limit reported by the most recent response
+// that carried one, or nil if none has been seen.
+func (c *Client) LastRateLimit() *RateLimit {
+	if c.rateLimit == nil {
+		return nil
+	}
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	if c.rateLimit.last == nil {
+		return nil
+	}
+	rl := *c.rateLimit.last
+	return &rl
+}
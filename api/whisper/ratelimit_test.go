@@ -0,0 +1,54 @@
+package whisper_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestLastRateLimit(t *testing.T) {
+	limited := jsonText("hello")
+	limited.Header = http.Header{
+		"Content-Type":                   {"application/json"},
+		"X-Ratelimit-Limit-Requests":     {"500"},
+		"X-Ratelimit-Remaining-Requests": {"499"},
+		"X-Ratelimit-Reset-Requests":     {"120ms"},
+		"X-Ratelimit-Limit-Tokens":       {"150000"},
+		"X-Ratelimit-Remaining-Tokens":   {"149000"},
+		"X-Ratelimit-Reset-Tokens":       {"6m0s"},
+	}
+	srv := whispertest.NewServer(limited, jsonText("no headers"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	if rl := c.LastRateLimit(); rl != nil {
+		t.Errorf("LastRateLimit() before any request = %+v, want nil", rl)
+	}
+	want := whisper.RateLimit{
+		LimitRequests:     500,
+		RemainingRequests: 499,
+		ResetRequests:     120 * time.Millisecond,
+		LimitTokens:       150000,
+		RemainingTokens:   149000,
+		ResetTokens:       6 * time.Minute,
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+			t.Fatal(err)
+		}
+		// A response without the headers keeps the last rate limit.
+		if rl := c.LastRateLimit(); rl == nil || *rl != want {
+			t.Errorf("LastRateLimit() after request %d = %+v, want %+v", i+1, rl, want)
+		}
+	}
+
+	c.LastRateLimit().RemainingRequests = 0
+	if rl := c.LastRateLimit(); rl.RemainingRequests != 499 {
+		t.Error("LastRateLimit() returned the Client's own copy")
+	}
+}
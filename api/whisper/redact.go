@@ -0,0 +1,47 @@
+package whisper
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+)
+
+// redacted replaces secrets in diagnostics.
+const redacted = "REDACTED"
+
+// sensitiveHeaders are the headers that always carry credentials.
+var sensitiveHeaders = []string{"Authorization", "Api-Key", "Proxy-Authorization"}
+
+// WithSensitiveHeader marks a header, e.g. one set with WithHeaders, as
+// carrying a secret so its value is redacted from logs and errors.
+func WithSensitiveHeader(name string) ClientOption {
+	return func(c *Client) {
+		c.sensitiveHeaders = append(c.sensitiveHeaders, http.CanonicalHeaderKey(name))
+	}
+}
+
+// redactHeaders returns a copy of h with the values of sensitive headers
+// replaced. All diagnostics that include headers must go through it.
+func (c *Client) redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for name := range out {
+		if slices.Contains(sensitiveHeaders, name) || slices.Contains(c.sensitiveHeaders, name) {
+			out[name] = []string{redacted}
+		}
+	}
+	return out
+}
+
+// secretRe matches bearer tokens and OpenAI-style API keys.
+var secretRe = regexp.MustCompile(`(?i)(bearer\s+)[^\s"',]+|\bsk-[A-Za-z0-9_\-]{8,}`)
+
+// redactSecrets replaces bearer tokens and API keys in s, for error messages
+// that may echo a request.
+func redactSecrets(s string) string {
+	return secretRe.ReplaceAllStringFunc(s, func(m string) string {
+		if sub := secretRe.FindStringSubmatch(m); sub[1] != "" {
+			return sub[1] + redacted
+		}
+		return redacted
+	})
+}
@@ -0,0 +1,85 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestDiagnosticsRedacted(t *testing.T) {
+	const (
+		key   = "sk-proj-secret0123456789"
+		vault = "vault-token-abcdef"
+	)
+	// echo answers with an error quoting the credentials it received, like a
+	// misbehaving proxy.
+	echo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprintf(w, `{"error":{"message":"upstream rejected Authorization: %s"}}`, r.Header.Get("Authorization"))
+	}))
+	defer echo.Close()
+	// dropping fails in the transport with an error quoting the request headers.
+	dropping := func(http.RoundTripper) http.RoundTripper {
+		return whisper.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("proxy dropped request with Authorization: %s", req.Header.Get("Authorization"))
+		})
+	}
+
+	tests := []struct {
+		name   string
+		client []whisper.ClientOption
+	}{
+		{"API error", []whisper.ClientOption{whisper.WithBaseURL(echo.URL)}},
+		{"API error after retries", []whisper.ClientOption{whisper.WithBaseURL(echo.URL), whisper.WithMaxRetries(1)}},
+		{"transport error", []whisper.ClientOption{whisper.WithBaseURL(echo.URL), whisper.WithRoundTripper(dropping)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			opts := append([]whisper.ClientOption{
+				whisper.WithKey(key),
+				whisper.WithHeaders(map[string]string{"X-Vault-Token": vault, "Api-Key": key}),
+				whisper.WithSensitiveHeader("x-vault-token"),
+				whisper.WithLogger(logger),
+			}, tt.client...)
+			c := whisper.NewClient(opts...)
+
+			_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+			if err == nil {
+				t.Fatal("Transcribe() succeeded, want an error")
+			}
+			var apiErr *whisper.APIError
+			var tErr *whisper.TransportError
+			if !errors.As(err, &apiErr) && !errors.As(err, &tErr) {
+				t.Fatalf("Transcribe() = %v, want an *APIError or *TransportError", err)
+			}
+			outputs := map[string]string{
+				"error":         err.Error(),
+				"verbose error": fmt.Sprintf("%+v", err),
+				"logs":          logs.String(),
+			}
+			if !strings.Contains(logs.String(), "REDACTED") {
+				t.Errorf("logs do not include the redacted request headers:\n%s", logs.String())
+			}
+			for name, out := range outputs {
+				for _, secret := range []string{key, vault} {
+					if strings.Contains(out, secret) {
+						t.Errorf("%s leaks %q:\n%s", name, secret, out)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package whisper
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RequestError wraps an error with the X-Request-ID of the request that failed.
+type RequestError struct {
+	ID  string
+	Err error
+	// ServerRequestIDs are the x-request-id values the server assigned to
+	// the failed attempts of the request, in order, including retries and
+	// failovers. Attempts that got no response have no ID.
+	ServerRequestIDs []string
+}
+
+func (e *RequestError) Error() string {
+	if len(e.ServerRequestIDs) > 1 {
+		return fmt.Sprintf("request %s: %v (request IDs of all attempts: %s)", e.ID, e.Err, strings.Join(e.ServerRequestIDs, ", "))
+	}
+	return fmt.Sprintf("request %s: %v", e.ID, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// RequestID returns the X-Request-ID of the failed request.
+func (e *RequestError) RequestID() string {
+	return e.ID
+}
+
+// appendServerRequestID appends the server request ID carried by err, if any, to ids.
+func appendServerRequestID(ids []string, err error) []string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RequestID != "" {
+		return append(ids, apiErr.RequestID)
+	}
+	return ids
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
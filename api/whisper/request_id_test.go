@@ -0,0 +1,60 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// withRequestID returns resp with the x-request-id header set to id.
+func withRequestID(resp whispertest.Response, id string) whispertest.Response {
+	resp.Header = resp.Header.Clone()
+	resp.Header.Set("X-Request-Id", id)
+	return resp
+}
+
+func TestServerRequestIDs(t *testing.T) {
+	// Each endpoint is tried twice; the primary's final 500 fails over.
+	primary := whispertest.NewServer(withRequestID(rateLimited(), "req_a"), withRequestID(whispertest.Error(http.StatusInternalServerError, "error"), "req_b"))
+	defer primary.Close()
+	fallback := whispertest.NewServer(withRequestID(rateLimited(), "req_c"), withRequestID(rateLimited(), "req_d"))
+	defer fallback.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(primary.URL), whisper.WithFallbackBaseURLs(fallback.URL), whisper.WithMaxRetries(1))
+
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithRequestID("call-1"))
+	var reqErr *whisper.RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("Transcribe() = %v, want a *RequestError", err)
+	}
+	if want := []string{"req_a", "req_b", "req_c", "req_d"}; !reflect.DeepEqual(reqErr.ServerRequestIDs, want) {
+		t.Errorf("ServerRequestIDs = %q, want %q", reqErr.ServerRequestIDs, want)
+	}
+	if reqErr.RequestID() != "call-1" {
+		t.Errorf("RequestID() = %q, want call-1", reqErr.RequestID())
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "request call-1: ") || !strings.HasSuffix(msg, "(request IDs of all attempts: req_a, req_b, req_c, req_d)") {
+		t.Errorf("Error() = %q, want the request IDs of all attempts", msg)
+	}
+}
+
+func TestServerRequestIDSingleAttempt(t *testing.T) {
+	srv := whispertest.NewServer(withRequestID(whispertest.Error(http.StatusBadRequest, "invalid file"), "req_a"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var reqErr *whisper.RequestError
+	if !errors.As(err, &reqErr) || !reflect.DeepEqual(reqErr.ServerRequestIDs, []string{"req_a"}) {
+		t.Fatalf("Transcribe() = %v, want a *RequestError with req_a", err)
+	}
+	if strings.Contains(err.Error(), "all attempts") {
+		t.Errorf("Error() = %q lists the request IDs of a single attempt", err)
+	}
+}
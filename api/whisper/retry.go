@@ -0,0 +1,140 @@
+package whisper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+)
+
+const (
+	// retryBaseDelay is the delay before the first retry; it doubles for each further attempt.
+	retryBaseDelay = 500 * time.Millisecond
+	// retryMaxDelay caps the delay between retries.
+	retryMaxDelay = 30 * time.Second
+)
+
+// WithMaxRetries retries a failed transcription up to n times with
+// exponential backoff. Errors for which IsRetryable reports true are retried;
+// for rate limits the delay the server asked for is waited instead. Audio
+// that is not an io.Seeker is buffered in memory so it can be re-sent.
+func WithMaxRetries(n int) ClientOption {
+	return func(c *Client) {
+		c.maxRetries = n
+	}
+}
+
+// RetryExhaustedError is returned when a request still fails after all
+// retries. It wraps the error of the last attempt.
+type RetryExhaustedError struct {
+	// Errors are the errors of all attempts, in order.
+	Errors []error
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("giving up after %d attempts: %v", len(e.Errors), e.Errors[len(e.Errors)-1])
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Errors[len(e.Errors)-1]
+}
+
+// IsRetryable reports whether a request that failed with err may succeed if
+// sent again: rate limits, 5xx responses and transient network errors such
+// as timeouts (ErrTimeout), refused or reset connections are. Requests whose
+// context was canceled or expired are not.
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrTimeout) {
+		return true
+	}
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.retryable()
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryDelay returns the delay before the retry following the given attempt,
+// which failed with err.
+func retryDelay(attempt int, err error) time.Duration {
+	var rlErr *RateLimitError
+	if errors.As(err, &rlErr) && rlErr.RetryAfter() > 0 {
+		return min(rlErr.RetryAfter(), retryMaxDelay)
+	}
+	return backoff(attempt)
+}
+
+// backoff returns the delay before the retry following the given attempt.
+func backoff(attempt int) time.Duration {
+	d := retryBaseDelay << attempt
+	if d <= 0 || d > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return d
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// replayBody lets the audio be read again for every attempt. Seekable
+// sources are rewound; anything else is buffered as it is read.
+type replayBody struct {
+	src    io.Reader
+	seeker io.Seeker
+	offset int64
+	buf    *bytes.Buffer
+	read   bool
+}
+
+func newReplayBody(h io.Reader) *replayBody {
+	b := &replayBody{src: h}
+	if s, ok := h.(io.Seeker); ok {
+		if off, err := s.Seek(0, io.SeekCurrent); err == nil {
+			b.seeker, b.offset = s, off
+			return b
+		}
+	}
+	b.buf = &bytes.Buffer{}
+	return b
+}
+
+// reader returns a reader over the complete audio. The reader returned by a
+// previous call must no longer be in use.
+func (b *replayBody) reader() (io.Reader, error) {
+	if b.seeker != nil {
+		if b.read {
+			if _, err := b.seeker.Seek(b.offset, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		b.read = true
+		return b.src, nil
+	}
+	return io.MultiReader(bytes.NewReader(b.buf.Bytes()), io.TeeReader(b.src, b.buf)), nil
+}
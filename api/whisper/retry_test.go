@@ -0,0 +1,138 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// rateLimited returns a 429 response that asks to retry after a few
+// milliseconds, keeping retry tests fast.
+func rateLimited() whispertest.Response {
+	r := whispertest.Error(http.StatusTooManyRequests, "Rate limit reached")
+	r.Header.Set("X-Ratelimit-Reset-Requests", "10ms")
+	return r
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+		opts    []transcribe.TranscribeOption
+		want    string // "" means any non-empty key
+		none    bool
+	}{
+		{name: "generated", retries: 2},
+		{name: "explicit", retries: 2, opts: []transcribe.TranscribeOption{transcribe.WithIdempotencyKey("key-1")}, want: "key-1"},
+		{name: "no retries", none: true},
+		{name: "no retries, explicit", opts: []transcribe.TranscribeOption{transcribe.WithIdempotencyKey("key-1")}, want: "key-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := []whispertest.Response{jsonText("hello")}
+			if tt.retries > 0 {
+				responses = []whispertest.Response{rateLimited(), rateLimited(), jsonText("hello")}
+			}
+			srv := whispertest.NewServer(responses...)
+			defer srv.Close()
+			c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithMaxRetries(tt.retries))
+
+			opts := append([]transcribe.TranscribeOption{transcribe.WithFile("a.wav")}, tt.opts...)
+			if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), opts...); err != nil {
+				t.Fatal(err)
+			}
+			reqs := srv.Requests()
+			if len(reqs) != tt.retries+1 {
+				t.Fatalf("server got %d requests, want %d", len(reqs), tt.retries+1)
+			}
+			key := reqs[0].Header.Get("Idempotency-Key")
+			switch {
+			case tt.none && key != "":
+				t.Errorf("Idempotency-Key = %q without retries, want none", key)
+			case !tt.none && key == "":
+				t.Error("no Idempotency-Key sent")
+			case tt.want != "" && key != tt.want:
+				t.Errorf("Idempotency-Key = %q, want %q", key, tt.want)
+			}
+			for i, req := range reqs[1:] {
+				if got := req.Header.Get("Idempotency-Key"); got != key {
+					t.Errorf("attempt %d sent Idempotency-Key %q, want %q as on the first attempt", i+2, got, key)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyPerCall(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithMaxRetries(1))
+	for i := 0; i < 2; i++ {
+		if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reqs := srv.Requests()
+	if a, b := reqs[0].Header.Get("Idempotency-Key"), reqs[1].Header.Get("Idempotency-Key"); a == b {
+		t.Errorf("two calls shared Idempotency-Key %q", a)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	srv := whispertest.NewServer(
+		whispertest.Error(http.StatusServiceUnavailable, "overloaded 1"),
+		whispertest.Error(http.StatusServiceUnavailable, "overloaded 2"),
+	)
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithMaxRetries(1))
+
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var exhausted *whisper.RetryExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("Transcribe() = %v, want a *RetryExhaustedError", err)
+	}
+	if len(exhausted.Errors) != 2 {
+		t.Errorf("RetryExhaustedError has %d errors, want 2", len(exhausted.Errors))
+	}
+	var apiErr *whisper.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || !strings.Contains(apiErr.Error(), "overloaded 2") {
+		t.Errorf("errors.As found %v, want the last attempt's 503", apiErr)
+	}
+	if !strings.Contains(err.Error(), "giving up after 2 attempts: ") {
+		t.Errorf("Error() = %q", err)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("server received %d requests, want 2", n)
+	}
+}
+
+func TestMetaAttempts(t *testing.T) {
+	tests := []struct {
+		responses []whispertest.Response
+		retries   int
+		want      int
+	}{
+		{[]whispertest.Response{jsonText("hello")}, 0, 1},
+		{[]whispertest.Response{jsonText("hello")}, 2, 1},
+		{[]whispertest.Response{rateLimited(), rateLimited(), jsonText("hello")}, 2, 3},
+	}
+	for _, tt := range tests {
+		srv := whispertest.NewServer(tt.responses...)
+		c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithMaxRetries(tt.retries))
+		tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+		srv.Close()
+		if err != nil {
+			t.Errorf("%d responses: %v", len(tt.responses), err)
+			continue
+		}
+		if tr.Meta.Attempts != tt.want {
+			t.Errorf("%d responses: Meta.Attempts = %d, want %d", len(tt.responses), tr.Meta.Attempts, tt.want)
+		}
+	}
+}
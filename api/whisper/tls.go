@@ -0,0 +1,88 @@
+package whisper
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// WithTLSConfig sets the TLS configuration used to connect to the API. The
+// configuration is cloned, as is any transport or HTTP client it is applied to.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = cfg.Clone()
+		c.tlsChanged = true
+	}
+}
+
+// WithClientCertificate adds a client certificate for mutual TLS, loaded
+// from PEM-encoded certificate and key files.
+func WithClientCertificate(certFile, keyFile string) ClientOption {
+	return func(c *Client) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.setErr(fmt.Errorf("loading client certificate: %w", err))
+			return
+		}
+		c.ensureTLSConfig().Certificates = append(c.tlsConfig.Certificates, cert)
+	}
+}
+
+// WithCACert trusts the PEM-encoded CA certificates in pemPath, in place of
+// the system roots, when verifying the server.
+func WithCACert(pemPath string) ClientOption {
+	return func(c *Client) {
+		pem, err := os.ReadFile(pemPath)
+		if err != nil {
+			c.setErr(fmt.Errorf("loading CA certificate: %w", err))
+			return
+		}
+		cfg := c.ensureTLSConfig()
+		if cfg.RootCAs == nil {
+			cfg.RootCAs = x509.NewCertPool()
+		}
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			c.setErr(fmt.Errorf("loading CA certificate: no certificates found in %s", pemPath))
+		}
+	}
+}
+
+// ensureTLSConfig returns the TLS configuration to modify, creating it if needed.
+func (c *Client) ensureTLSConfig() *tls.Config {
+	if c.tlsConfig == nil {
+		c.tlsConfig = &tls.Config{}
+	}
+	c.tlsChanged = true
+	return c.tlsConfig
+}
+
+// applyTLS installs the TLS configuration on a copy of the Client's transport.
+func (c *Client) applyTLS() error {
+	if c.httpClient == nil {
+		if c.transport == nil {
+			c.transport = newTransport()
+		} else {
+			c.transport = c.transport.Clone()
+		}
+		c.transport.TLSClientConfig = c.tlsConfig
+		return nil
+	}
+
+	rt := c.httpClient.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return errors.New("TLS options require the HTTP client to use an *http.Transport")
+	}
+	t = t.Clone()
+	t.TLSClientConfig = c.tlsConfig
+	hc := *c.httpClient
+	hc.Transport = t
+	c.httpClient = &hc
+	return nil
+}
@@ -0,0 +1,185 @@
+package whisper_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// writePEM writes blocks of the given type to a file in dir and returns its path.
+func writePEM(t *testing.T, dir, name, typ string, blocks ...[]byte) string {
+	t.Helper()
+	var b bytes.Buffer
+	for _, der := range blocks {
+		pem.Encode(&b, &pem.Block{Type: typ, Bytes: der})
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, b.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// clientCert creates a self-signed client certificate and returns the paths
+// of its certificate and key files.
+func clientCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "whisper-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writePEM(t, dir, "client.pem", "CERTIFICATE", der), writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+// tlsServer starts an HTTPS server answering transcriptions with the common
+// name of the client certificate, if any, and returns it with the path of a
+// file holding its certificate.
+func tlsServer(t *testing.T, clientAuth tls.ClientAuthType) (*httptest.Server, string) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := "anonymous"
+		if certs := r.TLS.PeerCertificates; len(certs) > 0 {
+			name = certs[0].Subject.CommonName
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"` + name + `"}`))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: clientAuth}
+	// Handshakes with untrusting clients fail on purpose.
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv, writePEM(t, t.TempDir(), "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+}
+
+func TestCACert(t *testing.T) {
+	srv, ca := tlsServer(t, tls.NoClientCert)
+
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+	if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err == nil {
+		t.Error("Transcribe() against an untrusted server succeeded")
+	}
+
+	c, err := whisper.New(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithCACert(ca))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Text != "anonymous" {
+		t.Errorf("Text = %q, want anonymous", tr.Text)
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	srv, ca := tlsServer(t, tls.RequireAnyClientCert)
+	certFile, keyFile := clientCert(t, t.TempDir())
+
+	c, err := whisper.New(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithCACert(ca), whisper.WithClientCertificate(certFile, keyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Text != "whisper-client" {
+		t.Errorf("server saw client certificate %q, want whisper-client", tr.Text)
+	}
+}
+
+func TestTLSOptionErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := clientCert(t, dir)
+	garbage := filepath.Join(dir, "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.pem")
+
+	roundTripper := &http.Client{Transport: whisper.RoundTripperFunc(http.DefaultTransport.RoundTrip)}
+	tests := map[string][]whisper.ClientOption{
+		"missing CA":           {whisper.WithCACert(missing)},
+		"invalid CA":           {whisper.WithCACert(garbage)},
+		"missing certificate":  {whisper.WithClientCertificate(missing, keyFile)},
+		"missing key":          {whisper.WithClientCertificate(certFile, missing)},
+		"invalid certificate":  {whisper.WithClientCertificate(garbage, keyFile)},
+		"mismatched key":       {whisper.WithClientCertificate(keyFile, certFile)},
+		"custom round tripper": {whisper.WithHTTPClient(roundTripper), whisper.WithTLSConfig(&tls.Config{})},
+	}
+	for name, opts := range tests {
+		if c, err := whisper.New(append([]whisper.ClientOption{whisper.WithKey("k")}, opts...)...); err == nil {
+			t.Errorf("%s: New() = %v, want an error", name, c)
+		}
+	}
+}
+
+func TestTLSOptionsCloneTransport(t *testing.T) {
+	srv, ca := tlsServer(t, tls.NoClientCert)
+
+	transport := &http.Transport{}
+	hc := &http.Client{Transport: transport, Timeout: time.Minute}
+	c, err := whisper.New(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithHTTPClient(hc), whisper.WithCACert(ca))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+		t.Fatal(err)
+	}
+	// http.Transport.Clone sets up HTTP/2 on the original transport, so the
+	// original is checked to still distrust the server instead.
+	if hc.Transport != transport {
+		t.Error("WithCACert replaced the transport of the HTTP client")
+	}
+	if resp, err := hc.Get(srv.URL); err == nil {
+		resp.Body.Close()
+		t.Error("WithCACert made the HTTP client's transport trust the CA")
+	}
+
+	own := &http.Transport{}
+	c, err = whisper.New(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithTransport(own), whisper.WithCACert(ca))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := (&http.Client{Transport: own}).Get(srv.URL); err == nil {
+		resp.Body.Close()
+		t.Error("WithCACert made the transport set with WithTransport trust the CA")
+	}
+}
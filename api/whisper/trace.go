@@ -0,0 +1,93 @@
+package whisper
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+)
+
+// WithConnectionStats records connection timings for every request in
+// TranscribeResponse.Meta.Timings.
+func WithConnectionStats() ClientOption {
+	return func(c *Client) {
+		c.connectionStats = true
+	}
+}
+
+// connTrace collects the timings of a single request from httptrace hooks,
+// which may be called from different goroutines.
+type connTrace struct {
+	mu      sync.Mutex
+	start   time.Time
+	dns     time.Time
+	connect time.Time
+	tls     time.Time
+	gotConn time.Time
+	timings models.Timings
+}
+
+// withConnTrace returns a context that records the request's timings into a new connTrace.
+func withConnTrace(ctx context.Context) (context.Context, *connTrace) {
+	t := &connTrace{start: time.Now()}
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dns = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.timings.DNS = time.Since(t.dns)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			t.connect = time.Now()
+			t.mu.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			t.mu.Lock()
+			t.timings.Connect = time.Since(t.connect)
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tls = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			t.timings.TLSHandshake = time.Since(t.tls)
+			t.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.gotConn = time.Now()
+			t.timings.ReusedConn = info.Reused
+			t.mu.Unlock()
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			t.timings.Upload = time.Since(t.gotConn)
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.timings.TimeToFirstByte = time.Since(t.start)
+			t.mu.Unlock()
+		},
+	})
+	return ctx, t
+}
+
+// Timings returns the timings recorded so far.
+func (t *connTrace) Timings() *models.Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timings := t.timings
+	return &timings
+}
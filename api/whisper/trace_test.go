@@ -0,0 +1,59 @@
+package whisper_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestConnectionStats(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"text":"hello"}`))
+	}))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithHTTPClient(srv.Client()), whisper.WithConnectionStats())
+
+	first, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	timings := first.Meta.Timings
+	if timings == nil {
+		t.Fatal("Meta.Timings not set")
+	}
+	if timings.Connect <= 0 || timings.TLSHandshake <= 0 || timings.Upload <= 0 || timings.TimeToFirstByte <= 0 || timings.ReusedConn {
+		t.Errorf("first request timings = %+v, want a new connection with all phases timed", timings)
+	}
+	if timings.TimeToFirstByte < timings.Connect+timings.TLSHandshake {
+		t.Errorf("TimeToFirstByte %s is less than connecting took", timings.TimeToFirstByte)
+	}
+
+	second, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	timings = second.Meta.Timings
+	if timings.DNS != 0 || timings.Connect != 0 || timings.TLSHandshake != 0 || !timings.ReusedConn || timings.TimeToFirstByte <= 0 {
+		t.Errorf("second request timings = %+v, want a reused connection without DNS, connect or TLS time", timings)
+	}
+}
+
+func TestConnectionStatsDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"text":"hello"}`))
+	}))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Meta.Timings != nil {
+		t.Errorf("Meta.Timings = %+v without WithConnectionStats, want nil", tr.Meta.Timings)
+	}
+}
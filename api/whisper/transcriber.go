@@ -0,0 +1,19 @@
+package whisper
+
+import (
+	"context"
+	"io"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// Transcriber is the context-aware transcription API of a Client. Code that
+// depends on it can swap backends or use whispertest.MockTranscriber in tests.
+type Transcriber interface {
+	TranscribeContext(ctx context.Context, h io.Reader, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error)
+	TranscribeFileContext(ctx context.Context, file string, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error)
+	TranscribeURL(ctx context.Context, audioURL string, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error)
+}
+
+var _ Transcriber = (*Client)(nil)
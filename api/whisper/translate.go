@@ -0,0 +1,26 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// ErrLanguageNotAllowed is matched by the error of a translation given a
+// language with WithLanguage, which the API would ignore.
+var ErrLanguageNotAllowed = errors.New("language not allowed for translations")
+
+// Translate transcribes the given audio stream and translates it into
+// English. It accepts the same options as Transcribe except WithLanguage,
+// which fails with an error matching ErrLanguageNotAllowed.
+func (c *Client) Translate(h io.Reader, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	return c.TranslateContext(context.Background(), h, opts...)
+}
+
+// TranslateContext is like Translate but carries a context for the request.
+func (c *Client) TranslateContext(ctx context.Context, h io.Reader, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	return c.call(ctx, translationsPath, h, opts)
+}
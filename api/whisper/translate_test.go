@@ -0,0 +1,39 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestTranslateLanguage(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+
+	_, err := c.Translate(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithLanguage("de"))
+	if !errors.Is(err, whisper.ErrLanguageNotAllowed) {
+		t.Errorf("Translate() with a language = %v, want ErrLanguageNotAllowed", err)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("server got %d requests, want none", n)
+	}
+
+	if _, err := c.Translate(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+		t.Fatalf("Translate() = %v", err)
+	}
+	if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithLanguage("de")); err != nil {
+		t.Fatalf("Transcribe() with a language = %v", err)
+	}
+	reqs := srv.Requests()
+	if reqs[0].Path != "/audio/translations" || reqs[0].Fields["language"] != nil {
+		t.Errorf("translation sent to %s with language %q, want /audio/translations without one", reqs[0].Path, reqs[0].Fields["language"])
+	}
+	if reqs[1].Path != "/audio/transcriptions" || len(reqs[1].Fields["language"]) != 1 {
+		t.Errorf("transcription sent to %s with language %q, want /audio/transcriptions with de", reqs[1].Path, reqs[1].Fields["language"])
+	}
+}
@@ -0,0 +1,50 @@
+package whisper
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// newTransport returns the transport used when no HTTP client or transport is
+// configured. It is tuned for uploading large files to a single host.
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		// Transcribing long audio can take minutes after the upload completes.
+		ResponseHeaderTimeout: 10 * time.Minute,
+	}
+}
+
+// WithTransport sets the transport of the HTTP client the Client creates. It
+// is ignored when an HTTP client is set with WithHTTPClient.
+func WithTransport(t *http.Transport) ClientOption {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// WithTransportTuning replaces the transport of the HTTP client the Client
+// creates with the default one tuned for connection reuse: maxIdleConns and
+// maxIdlePerHost bound the pooled idle connections, idleTimeout how long they
+// are kept. Like WithTransport it is ignored when an HTTP client is set with
+// WithHTTPClient; of WithTransport and WithTransportTuning the last one wins.
+func WithTransportTuning(maxIdleConns, maxIdlePerHost int, idleTimeout time.Duration) ClientOption {
+	return func(c *Client) {
+		t := newTransport()
+		t.MaxIdleConns = maxIdleConns
+		t.MaxIdleConnsPerHost = maxIdlePerHost
+		t.IdleConnTimeout = idleTimeout
+		c.transport = t
+	}
+}
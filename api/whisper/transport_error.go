@@ -0,0 +1,160 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Phase is the stage of a request at which it failed.
+type Phase string
+
+const (
+	// PhaseConnect means no connection to the server was established.
+	PhaseConnect Phase = "connect"
+	// PhaseUpload means the request was being sent.
+	PhaseUpload Phase = "upload"
+	// PhaseResponse means the request was sent and the response was
+	// awaited or being read.
+	PhaseResponse Phase = "response"
+)
+
+// TransportError is returned when a request fails without a complete
+// response, for example because the connection could not be established,
+// was dropped, or the context was canceled or timed out. errors.Is reports
+// the underlying cause, such as context.DeadlineExceeded or
+// syscall.ECONNREFUSED. Its message names the endpoint and the audio but
+// never includes query strings or headers.
+type TransportError struct {
+	Phase Phase
+	// BytesSent is the number of request body bytes handed to the
+	// connection before the failure.
+	BytesSent int64
+	// Host and Path identify the endpoint of the request.
+	Host string
+	Path string
+	// File is the filename of the audio, if any.
+	File string
+	// Size is the size of the audio in bytes, or -1 if unknown.
+	Size int64
+	Err  error
+	// Elapsed is the time from sending the request until it failed.
+	Elapsed time.Duration
+
+	timeout bool
+}
+
+func (e *TransportError) Error() string {
+	var b strings.Builder
+	b.WriteString(string(e.Phase))
+	if e.Host != "" {
+		fmt.Fprintf(&b, " to %s%s", e.Host, e.Path)
+	}
+	b.WriteString(" failed")
+	if e.Phase == PhaseUpload {
+		fmt.Fprintf(&b, " after %d bytes", e.BytesSent)
+	}
+	if e.File != "" {
+		fmt.Fprintf(&b, " (file %s", e.File)
+		if e.Size >= 0 {
+			fmt.Fprintf(&b, ", %d bytes", e.Size)
+		}
+		b.WriteString(")")
+	}
+	// The *url.Error message repeats the full URL, query included.
+	cause := e.Err
+	var urlErr *url.Error
+	if errors.As(cause, &urlErr) {
+		cause = urlErr.Err
+	}
+	b.WriteString(": ")
+	b.WriteString(redactSecrets(cause.Error()))
+	return b.String()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout reports whether the request timed out in the HTTP client or the
+// network, as opposed to the caller's context being done.
+func (e *TransportError) Timeout() bool {
+	return e.timeout
+}
+
+// Is makes errors.Is(err, ErrTimeout) true for timeouts.
+func (e *TransportError) Is(target error) bool {
+	return target == ErrTimeout && e.timeout
+}
+
+// progress tracks how far a request got, to attribute a failure to a Phase.
+type progress struct {
+	sent      atomic.Int64
+	connected atomic.Bool
+	wrote     atomic.Bool
+}
+
+// withProgress returns a context that records the request's progress into p.
+func withProgress(ctx context.Context, p *progress) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) { p.connected.Store(true) },
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				p.wrote.Store(true)
+			}
+		},
+	})
+}
+
+// body returns r counting the bytes read from it as sent.
+func (p *progress) body(r io.Reader) io.Reader {
+	return &countingReader{r: r, n: &p.sent}
+}
+
+// phase returns the phase the request is in.
+func (p *progress) phase() Phase {
+	switch {
+	case !p.connected.Load():
+		return PhaseConnect
+	case !p.wrote.Load():
+		return PhaseUpload
+	}
+	return PhaseResponse
+}
+
+// transportError wraps err, which ended req after elapsed, tracked by p.
+// file and size describe the audio.
+func (c *Client) transportError(ctx context.Context, p *progress, req *http.Request, err error, elapsed time.Duration, file string, size int64) *TransportError {
+	var netErr net.Error
+	return &TransportError{
+		Phase:     p.phase(),
+		BytesSent: p.sent.Load(),
+		Host:      req.URL.Host,
+		Path:      req.URL.Path,
+		File:      file,
+		Size:      size,
+		Err:       err,
+		Elapsed:   elapsed,
+		timeout:   ctx.Err() == nil && errors.As(err, &netErr) && netErr.Timeout(),
+	}
+}
+
+// countingReader counts the bytes read from r into n.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
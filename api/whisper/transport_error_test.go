@@ -0,0 +1,66 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func TestTransportErrorConnect(t *testing.T) {
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(closedURL()))
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"))
+	var tErr *whisper.TransportError
+	if !errors.As(err, &tErr) {
+		t.Fatalf("Transcribe() = %v, want a *TransportError", err)
+	}
+	if tErr.Phase != whisper.PhaseConnect || tErr.BytesSent != 0 {
+		t.Errorf("Phase, BytesSent = %s, %d; want connect, 0", tErr.Phase, tErr.BytesSent)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Errorf("errors.Is(%v, ECONNREFUSED) = false", err)
+	}
+	if !strings.Contains(err.Error(), ": connect to ") || !strings.Contains(err.Error(), "(file a.wav, 8044 bytes)") {
+		t.Errorf("Error() = %q", err)
+	}
+}
+
+func TestTransportErrorUpload(t *testing.T) {
+	// The server reads the start of the request and then closes the
+	// connection, resetting the rest of the upload.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			io.CopyN(io.Discard, conn, 64<<10)
+			conn.Close()
+		}
+	}()
+
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL("http://"+ln.Addr().String()))
+	audio := wavFile(2000) // 16 MB, more than the socket buffers hold
+	_, err = c.Transcribe(bytes.NewReader(audio), transcribe.WithFile("a.wav"))
+	var tErr *whisper.TransportError
+	if !errors.As(err, &tErr) {
+		t.Fatalf("Transcribe() = %v, want a *TransportError", err)
+	}
+	if tErr.Phase != whisper.PhaseUpload || tErr.BytesSent <= 0 || tErr.BytesSent >= int64(len(audio)) {
+		t.Errorf("Phase, BytesSent = %s, %d; want upload with part of the %d bytes sent", tErr.Phase, tErr.BytesSent, len(audio))
+	}
+	if !strings.Contains(err.Error(), "upload to "+ln.Addr().String()+"/audio/transcriptions failed after") {
+		t.Errorf("Error() = %q", err)
+	}
+}
@@ -0,0 +1,39 @@
+package whisper
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTransportTuning(t *testing.T) {
+	c := NewClient(WithTransportTuning(500, 64, 2*time.Minute))
+	tr, ok := c.baseHTTPClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport is %T, want *http.Transport", c.baseHTTPClient.Transport)
+	}
+	if tr.MaxIdleConns != 500 || tr.MaxIdleConnsPerHost != 64 || tr.IdleConnTimeout != 2*time.Minute {
+		t.Errorf("transport pools %d connections, %d per host, for %v; want 500, 64, 2m0s",
+			tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.IdleConnTimeout)
+	}
+	if def := newTransport(); tr.ResponseHeaderTimeout != def.ResponseHeaderTimeout || tr.Proxy == nil {
+		t.Error("tuned transport lost the other defaults")
+	}
+}
+
+func TestTransportTuningPrecedence(t *testing.T) {
+	hc := &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: 2}}
+	c := NewClient(WithTransportTuning(500, 64, time.Minute), WithHTTPClient(hc))
+	if c.baseHTTPClient != hc || hc.Transport.(*http.Transport).MaxIdleConnsPerHost != 2 {
+		t.Error("WithTransportTuning changed the client set with WithHTTPClient")
+	}
+
+	custom := &http.Transport{MaxIdleConnsPerHost: 3}
+	if c := NewClient(WithTransportTuning(500, 64, time.Minute), WithTransport(custom)); c.baseHTTPClient.Transport != custom {
+		t.Error("WithTransport given after WithTransportTuning did not win")
+	}
+	c = NewClient(WithTransport(custom), WithTransportTuning(500, 64, time.Minute))
+	if tr := c.baseHTTPClient.Transport.(*http.Transport); tr == custom || tr.MaxIdleConnsPerHost != 64 {
+		t.Error("WithTransportTuning given after WithTransport did not win")
+	}
+}
@@ -0,0 +1,210 @@
+package whisper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// WithMultipartBoundary makes requests use the given multipart boundary
+// instead of a random one, so request bodies are reproducible. It is meant
+// for tests; the boundary must be valid per RFC 2046 and must not occur in
+// the audio.
+func WithMultipartBoundary(boundary string) ClientOption {
+	return func(c *Client) {
+		if err := multipart.NewWriter(io.Discard).SetBoundary(boundary); err != nil {
+			c.setErr(fmt.Errorf("multipart boundary: %w", err))
+			return
+		}
+		c.boundary = boundary
+	}
+}
+
+// DefaultFileFieldName is the form field the audio is uploaded in.
+const DefaultFileFieldName = "file"
+
+// WithFileFieldName sets the form field the audio is uploaded in, for
+// servers that expect another name than "file", e.g. "audio_file".
+func WithFileFieldName(name string) ClientOption {
+	return func(c *Client) {
+		c.fileField = name
+	}
+}
+
+// formBody builds the multipart request bodies of a call. Each body streams
+// a fresh form with the audio returned by audio, so that a request can be
+// replayed through http.Request.GetBody, e.g. when it is redirected.
+//
+// The form is streamed through a pipe so the audio is never fully buffered
+// in memory. If reading the audio fails, the pipe is closed with the error,
+// which aborts the request instead of completing a truncated form.
+type formBody struct {
+	c     *Client
+	tc    *transcribe.TranscribeConfig
+	audio func() (io.Reader, error)
+
+	mu       sync.Mutex
+	boundary string
+	pr       *io.PipeReader
+	done     chan error
+	err      error
+}
+
+// open stops the writer of the previous body and returns a new body and its
+// content type.
+func (f *formBody) open() (io.Reader, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// The previous writer must be done with the audio before it is rewound.
+	f.stop()
+	h, err := f.audio()
+	if err != nil {
+		return nil, "", err
+	}
+	pr, pw := io.Pipe()
+	mp := multipart.NewWriter(pw)
+	// All bodies share a boundary, as it is part of the content type.
+	if f.boundary == "" {
+		f.boundary = f.c.boundary
+	}
+	if f.boundary != "" {
+		mp.SetBoundary(f.boundary)
+	}
+	f.boundary = mp.Boundary()
+	done := make(chan error, 1)
+	go func() {
+		err := writeForm(mp, f.c.fileField, f.tc, &uploadLimitReader{r: h, n: f.c.maxUploadSize, file: f.tc.File})
+		pw.CloseWithError(err)
+		done <- err
+	}()
+	f.pr, f.done, f.err = pr, done, nil
+	return pr, mp.FormDataContentType(), nil
+}
+
+// finish stops the upload of the current body if it is still running and
+// waits for its writer to return, so that the audio is no longer read. It
+// returns the writer's error.
+func (f *formBody) finish() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stop()
+	return f.err
+}
+
+func (f *formBody) stop() {
+	if f.done == nil {
+		return
+	}
+	f.pr.Close()
+	f.err = <-f.done
+	f.done = nil
+}
+
+// audioOnce returns an audio source for formBody that yields h. Seekable
+// readers are rewound for every body; others can be read only once, as
+// buffering them would defeat streaming the upload.
+func audioOnce(h io.Reader) func() (io.Reader, error) {
+	if _, ok := h.(io.Seeker); ok {
+		return newReplayBody(h).reader
+	}
+	used := false
+	return func() (io.Reader, error) {
+		if used {
+			return nil, errors.New("cannot replay audio from a reader that is not seekable")
+		}
+		used = true
+		return h, nil
+	}
+}
+
+// writeForm writes the multipart form for a transcription request, copying
+// the audio from h into the fileField part, and closes the writer.
+func writeForm(mp *multipart.Writer, fileField string, tc *transcribe.TranscribeConfig, h io.Reader) error {
+	fields := [][2]string{
+		{"model", tc.Model},
+		{"response_format", tc.ResponseFormat},
+		{"language", tc.Language},
+		{"prompt", tc.Prompt},
+	}
+	for _, g := range tc.TimestampGranularities {
+		fields = append(fields, [2]string{"timestamp_granularities[]", g})
+	}
+	for _, item := range tc.Include {
+		fields = append(fields, [2]string{"include[]", item})
+	}
+	fields = append(fields, tc.ExtraFields...)
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := mp.WriteField(field[0], field[1]); err != nil {
+			return err
+		}
+	}
+
+	hdr := make(textproto.MIMEHeader)
+	hdr.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(fileField), quoteEscaper.Replace(tc.File)))
+	hdr.Set("Content-Type", contentTypeFor(tc.File))
+	fp, err := mp.CreatePart(hdr)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(fp, h); err != nil {
+		return err
+	}
+	return mp.Close()
+}
+
+// uploadLimitReader reads the audio from r and fails with ErrFileTooLarge as
+// soon as it yields more than n bytes, so oversized streams are not uploaded
+// in full.
+type uploadLimitReader struct {
+	r    io.Reader
+	n    int64
+	read int64
+	file string
+}
+
+func (l *uploadLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.n {
+		return 0, fmt.Errorf("%s: %w (more than %d bytes)", l.file, ErrFileTooLarge, l.n)
+	}
+	return n, err
+}
+
+// quoteEscaper escapes quoted-string values in multipart headers.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// sizeOf returns the number of bytes remaining in h if it can be determined
+// without reading it, or -1 otherwise.
+func sizeOf(h io.Reader) int64 {
+	switch v := h.(type) {
+	case *bytes.Buffer:
+		return int64(v.Len())
+	case *bytes.Reader:
+		return int64(v.Len())
+	case *strings.Reader:
+		return int64(v.Len())
+	case *os.File:
+		fi, err := v.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return -1
+		}
+		off, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return fi.Size() - off
+	}
+	return -1
+}
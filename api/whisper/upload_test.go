@@ -0,0 +1,264 @@
+package whisper_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+var errHiccup = errors.New("network hiccup")
+
+// failingReader returns the first n bytes of data and then fails with errHiccup.
+type failingReader struct {
+	data []byte
+	n    int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, errHiccup
+	}
+	p = p[:min(len(p), r.n, len(r.data))]
+	n := copy(p, r.data)
+	r.data, r.n = r.data[n:], r.n-n
+	return n, nil
+}
+
+func TestUploadReadError(t *testing.T) {
+	for _, n := range []int{0, 100, 20000} {
+		srv := whispertest.NewServer(jsonText("hello"))
+		c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+		_, err := c.Transcribe(&failingReader{data: wavFile(5), n: n}, transcribe.WithFile("a.wav"))
+		if !errors.Is(err, errHiccup) {
+			t.Errorf("read error after %d bytes: Transcribe() = %v, want the read error", n, err)
+		}
+		if reqs := srv.Requests(); len(reqs) != 0 {
+			t.Errorf("read error after %d bytes: server received a complete upload of %d bytes", n, len(reqs[0].Audio))
+		}
+		srv.Close()
+	}
+}
+
+func TestUploadReadErrorNotRetried(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithMaxRetries(2))
+	_, err := c.Transcribe(io.MultiReader(bytes.NewReader(wavFile(1)), &failingReader{}), transcribe.WithFile("a.wav"))
+	if !errors.Is(err, errHiccup) {
+		t.Errorf("Transcribe() = %v, want the read error", err)
+	}
+	if reqs := srv.Requests(); len(reqs) != 0 {
+		t.Errorf("server received %d complete uploads, want none", len(reqs))
+	}
+}
+
+func TestExtraFormFields(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+	_, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"),
+		transcribe.WithExtraFormField("hotwords", "Kubernetes gRPC"),
+		transcribe.WithExtraFormField("beam_size", "5"),
+		transcribe.WithExtraFormField("hotwords", "Istio"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := srv.Requests()[0].Fields
+	if got, want := fields["hotwords"], []string{"Kubernetes gRPC", "Istio"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hotwords = %q, want %q", got, want)
+	}
+	if got, want := fields["beam_size"], []string{"5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("beam_size = %q, want %q", got, want)
+	}
+	if got := fields["model"]; len(got) != 1 {
+		t.Errorf("model = %q, want the one model field", got)
+	}
+}
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// golden compares got with the golden file testdata/name, rewriting the file
+// instead when the tests run with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from %s:\n%s", name, path, got)
+	}
+}
+
+// captureBodies returns a server answering with a transcript that appends
+// the Content-Type and body of every request to bodies.
+func captureBodies(mu *sync.Mutex, bodies *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		*bodies = append(*bodies, r.Header.Get("Content-Type")+"\n"+string(b))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"text":"hello"}`)
+	}))
+}
+
+func TestMultipartBoundaryGolden(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	srv := captureBodies(&mu, &bodies)
+	defer srv.Close()
+
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithMultipartBoundary("golden-boundary"))
+	for i := 0; i < 2; i++ {
+		_, err := c.Transcribe(strings.NewReader("OggS fake audio payload\n"), transcribe.WithFile("talk.ogg"),
+			transcribe.WithLanguage("de"), transcribe.WithPrompt("Kubernetes"), transcribe.WithExtraFormField("hotwords", "Istio"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bodies[0] != bodies[1] {
+		t.Error("requests with a fixed boundary differ")
+	}
+	golden(t, "transcribe_request.golden", []byte(bodies[0]))
+}
+
+func TestMultipartBoundaryDefault(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	srv := captureBodies(&mu, &bodies)
+	defer srv.Close()
+
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL))
+	for i := 0; i < 2; i++ {
+		if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bodies[0] == bodies[1] {
+		t.Error("two requests used the same random boundary")
+	}
+}
+
+func TestMultipartBoundaryInvalid(t *testing.T) {
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithMultipartBoundary("not valid!"))
+	if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err == nil {
+		t.Error("Transcribe() with an invalid boundary succeeded")
+	}
+}
+
+func TestFileFieldName(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	for _, tt := range []struct {
+		opts []whisper.ClientOption
+		want string
+	}{
+		{nil, "file"},
+		{[]whisper.ClientOption{whisper.WithFileFieldName("audio_file")}, "audio_file"},
+	} {
+		c := whisper.NewClient(append([]whisper.ClientOption{whisper.WithKey("k"), whisper.WithBaseURL(srv.URL)}, tt.opts...)...)
+		if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+			t.Fatal(err)
+		}
+		reqs := srv.Requests()
+		req := reqs[len(reqs)-1]
+		if req.FileField != tt.want || req.File != "a.wav" || !bytes.Equal(req.Audio, wavFile(1)) {
+			t.Errorf("audio uploaded as %s in field %q, want a.wav in %q", req.File, req.FileField, tt.want)
+		}
+		if _, ok := req.Fields[tt.want]; ok {
+			t.Errorf("field %q also sent as a plain field", tt.want)
+		}
+	}
+}
+
+func TestGetBodyReplay(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	// replay reads the whole body, then sends a replayed copy instead.
+	var first, replayed []byte
+	replay := func(next http.RoundTripper) http.RoundTripper {
+		return whisper.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			var err error
+			if first, err = io.ReadAll(req.Body); err != nil {
+				return nil, err
+			}
+			if req.GetBody == nil {
+				return nil, errors.New("GetBody not set")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			if replayed, err = io.ReadAll(body); err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(req)
+		})
+	}
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithRoundTripper(replay))
+	if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+		t.Fatal(err)
+	}
+	if len(first) == 0 || !bytes.Equal(first, replayed) {
+		t.Errorf("replayed body of %d bytes differs from the original of %d bytes", len(replayed), len(first))
+	}
+	if got := srv.Requests()[0].Audio; !bytes.Equal(got, wavFile(1)) {
+		t.Errorf("server got %d bytes of audio from the replayed body, want the complete audio", len(got))
+	}
+}
+
+func TestRedirectReplaysBody(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, srv.URL+r.URL.Path, http.StatusTemporaryRedirect)
+	}))
+	defer redirect.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(redirect.URL))
+
+	if _, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav")); err != nil {
+		t.Fatal(err)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 1 || !bytes.Equal(reqs[0].Audio, wavFile(1)) {
+		t.Fatalf("redirected request did not carry the complete audio")
+	}
+
+	// A reader that cannot be rewound cannot follow the redirect.
+	_, err := c.Transcribe(io.MultiReader(bytes.NewReader(wavFile(1))), transcribe.WithFile("a.wav"))
+	if err == nil {
+		t.Error("Transcribe() from an unseekable reader followed the redirect")
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("server got %d requests, want no second upload", n)
+	}
+}
@@ -0,0 +1,83 @@
+package whisper
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+)
+
+// Usage is a snapshot of the audio transcribed by a Client with cost tracking enabled.
+type Usage struct {
+	Requests      int64   `json:"requests"`
+	Seconds       float64 `json:"seconds"`
+	EstimatedCost float64 `json:"estimated_cost"`
+	// Unpriced counts the requests, included in Requests, whose audio
+	// duration and so cost is unknown.
+	Unpriced int64 `json:"unpriced"`
+}
+
+// usageTracker accumulates Usage for a Client.
+type usageTracker struct {
+	pricePerMinute float64
+
+	mu    sync.Mutex
+	usage Usage
+}
+
+// WithCostTracking enables accumulating transcribed seconds and estimated cost
+// at the given price per minute of audio. The duration is taken from the
+// response if its format reports it (verbose_json), and is otherwise
+// determined locally like for WithMaxCostPerRequest. Requests whose duration
+// is unknown are counted in Usage.Unpriced and have Meta.CostKnown unset.
+func WithCostTracking(pricePerMinute float64) ClientOption {
+	return func(c *Client) {
+		c.usage = &usageTracker{pricePerMinute: pricePerMinute}
+	}
+}
+
+// record adds a successful request and returns its estimated cost. probed is
+// the duration of the audio determined locally, used if the response does not
+// report one. It reports whether the cost is known.
+func (u *usageTracker) record(tr *models.TranscribeResponse, probed time.Duration) (float64, bool) {
+	seconds := tr.Duration
+	if seconds == 0 {
+		seconds = probed.Seconds()
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.usage.Requests++
+	if seconds == 0 {
+		u.usage.Unpriced++
+		return 0, false
+	}
+	cost := math.Round(seconds) / 60 * u.pricePerMinute
+	u.usage.Seconds += seconds
+	u.usage.EstimatedCost += cost
+	return cost, true
+}
+
+// Usage returns the usage accumulated since the Client was created or last
+// reset. It is zero if cost tracking is not enabled.
+func (c *Client) Usage() Usage {
+	if c.usage == nil {
+		return Usage{}
+	}
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	return c.usage.usage
+}
+
+// ResetUsage clears the accumulated usage and returns its last value.
+func (c *Client) ResetUsage() Usage {
+	if c.usage == nil {
+		return Usage{}
+	}
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	u := c.usage.usage
+	c.usage.usage = Usage{}
+	return u
+}
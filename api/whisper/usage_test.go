@@ -0,0 +1,94 @@
+package whisper_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// wavFile returns a silent 8 kHz mono 8-bit WAV file of the given length.
+func wavFile(seconds int) []byte {
+	const rate = 8000
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(36+rate*seconds))
+	b.WriteString("WAVEfmt ")
+	for _, v := range []any{uint32(16), uint16(1), uint16(1), uint32(rate), uint32(rate), uint16(1), uint16(8)} {
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(rate*seconds))
+	b.Write(bytes.Repeat([]byte{0x80}, rate*seconds))
+	return b.Bytes()
+}
+
+func jsonText(text string) whispertest.Response {
+	return whispertest.Response{Header: http.Header{"Content-Type": {"application/json"}}, Body: `{"text":"` + text + `"}`}
+}
+
+func TestCostTrackingVerboseJSON(t *testing.T) {
+	srv := whispertest.NewServer(whispertest.Transcript(&models.TranscribeResponse{
+		Task:     "transcribe",
+		Text:     "hello",
+		Duration: 90,
+		Segments: []models.Segment{{Text: "hello", End: 90}},
+	}))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithCostTracking(0.006))
+
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(1)), transcribe.WithFile("a.wav"), transcribe.WithResponseFormat("verbose_json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tr.Meta.CostKnown || math.Abs(tr.Meta.Cost-0.009) > 1e-9 {
+		t.Errorf("Meta cost = %v, %v; want 0.009, true", tr.Meta.Cost, tr.Meta.CostKnown)
+	}
+	if u := c.Usage(); u.Requests != 1 || u.Seconds != 90 || u.Unpriced != 0 {
+		t.Errorf("Usage() = %+v", u)
+	}
+}
+
+func TestCostTrackingFallsBackToProbedDuration(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithCostTracking(0.006))
+
+	tr, err := c.Transcribe(bytes.NewReader(wavFile(30)), transcribe.WithFile("a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tr.Meta.CostKnown || math.Abs(tr.Meta.Cost-0.003) > 1e-9 {
+		t.Errorf("Meta cost = %v, %v; want 0.003, true", tr.Meta.Cost, tr.Meta.CostKnown)
+	}
+	if u := c.Usage(); u.Requests != 1 || u.Seconds != 30 || u.Unpriced != 0 {
+		t.Errorf("Usage() = %+v", u)
+	}
+	if got := srv.Requests()[0].Audio; !bytes.Equal(got, wavFile(30)) {
+		t.Errorf("uploaded %d bytes, want the complete audio", len(got))
+	}
+}
+
+func TestCostTrackingUnknownDuration(t *testing.T) {
+	srv := whispertest.NewServer(jsonText("hello"))
+	defer srv.Close()
+	c := whisper.NewClient(whisper.WithKey("k"), whisper.WithBaseURL(srv.URL), whisper.WithCostTracking(0.006))
+
+	ogg := append([]byte("OggS"), make([]byte, 100)...)
+	tr, err := c.Transcribe(bytes.NewReader(ogg), transcribe.WithFile("a.ogg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Meta.CostKnown || tr.Meta.Cost != 0 {
+		t.Errorf("Meta cost = %v, %v; want 0, false", tr.Meta.Cost, tr.Meta.CostKnown)
+	}
+	if u := c.Usage(); u.Requests != 1 || u.Unpriced != 1 || u.EstimatedCost != 0 {
+		t.Errorf("Usage() = %+v", u)
+	}
+}
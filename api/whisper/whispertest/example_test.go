@@ -0,0 +1,42 @@
+package whispertest_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+func Example() {
+	// A rate limit that clears immediately, then a transcript.
+	srv := whispertest.NewServer(
+		whispertest.RateLimited(0),
+		whispertest.Transcript(&models.TranscribeResponse{
+			Task:     "transcribe",
+			Language: "english",
+			Duration: 1.5,
+			Text:     "Hello, world.",
+			Segments: []models.Segment{{Start: 0, End: 1.5, Text: " Hello, world."}},
+		}),
+	)
+	defer srv.Close()
+
+	c := whisper.NewClient(whisper.WithKey("test-key"), whisper.WithBaseURL(srv.URL), whisper.WithMaxRetries(1))
+	tr, err := c.Transcribe(strings.NewReader("OggS audio"), transcribe.WithFile("hello.ogg"), transcribe.WithResponseFormat("verbose_json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(tr.Text)
+
+	reqs := srv.Requests()
+	fmt.Println(len(reqs), "requests")
+	fmt.Println(reqs[1].File, reqs[1].Fields["model"][0], reqs[1].Fields["response_format"][0])
+	// Output:
+	// Hello, world.
+	// 2 requests
+	// hello.ogg whisper-1 verbose_json
+}
@@ -0,0 +1,103 @@
+// Package whispertest provides test doubles for code using the whisper package.
+package whispertest
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// Call records a request made to a MockTranscriber.
+type Call struct {
+	Config transcribe.TranscribeConfig
+	Audio  []byte
+}
+
+// MockTranscriber is a whisper.Transcriber that returns canned responses.
+// Responses are returned in order; the last one is repeated once exhausted.
+type MockTranscriber struct {
+	Responses []*models.TranscribeResponse
+	// Err, if set, is returned instead of a response.
+	Err error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ whisper.Transcriber = (*MockTranscriber)(nil)
+
+// NewMockTranscriber returns a MockTranscriber that returns the given responses.
+func NewMockTranscriber(responses ...*models.TranscribeResponse) *MockTranscriber {
+	return &MockTranscriber{Responses: responses}
+}
+
+// TranscribeContext records the call and returns the next canned response.
+func (m *MockTranscriber) TranscribeContext(ctx context.Context, h io.Reader, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var tc transcribe.TranscribeConfig
+	for _, opt := range opts {
+		opt(&tc)
+	}
+	audio, err := io.ReadAll(h)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Config: tc, Audio: audio})
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if len(m.Responses) == 0 {
+		return &models.TranscribeResponse{}, nil
+	}
+	i := len(m.calls) - 1
+	if i >= len(m.Responses) {
+		i = len(m.Responses) - 1
+	}
+	tr := *m.Responses[i]
+	return &tr, nil
+}
+
+// TranscribeFileContext records the call with the file's contents and returns
+// the next canned response.
+func (m *MockTranscriber) TranscribeFileContext(ctx context.Context, file string, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	h, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer h.Close()
+
+	opts = append([]transcribe.TranscribeOption{transcribe.WithFile(filepath.Base(file))}, opts...)
+	return m.TranscribeContext(ctx, h, opts...)
+}
+
+// TranscribeURL records the call without fetching the URL and returns the
+// next canned response.
+func (m *MockTranscriber) TranscribeURL(ctx context.Context, audioURL string, opts ...transcribe.TranscribeOption) (*models.TranscribeResponse, error) {
+	name := audioURL
+	if u, err := url.Parse(audioURL); err == nil {
+		name = path.Base(u.Path)
+	}
+	opts = append([]transcribe.TranscribeOption{transcribe.WithFile(name)}, opts...)
+	return m.TranscribeContext(ctx, strings.NewReader(""), opts...)
+}
+
+// Calls returns the calls made so far.
+func (m *MockTranscriber) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
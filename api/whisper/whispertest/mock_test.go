@@ -0,0 +1,81 @@
+package whispertest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
+)
+
+// summarize depends only on the Transcriber interface, the way callers of the
+// whisper package are expected to.
+func summarize(ctx context.Context, t whisper.Transcriber, audio string) (string, error) {
+	tr, err := t.TranscribeContext(ctx, strings.NewReader(audio), transcribe.WithFile("a.wav"), transcribe.WithLanguage("en"))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(tr.Text), nil
+}
+
+func TestMockTranscriber(t *testing.T) {
+	m := whispertest.NewMockTranscriber(&models.TranscribeResponse{Text: "one"}, &models.TranscribeResponse{Text: "two"})
+	for _, want := range []string{"ONE", "TWO", "TWO"} {
+		got, err := summarize(context.Background(), m, "audio")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("summarize() = %q, want %q", got, want)
+		}
+	}
+
+	calls := m.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("recorded %d calls, want 3", len(calls))
+	}
+	if c := calls[0]; string(c.Audio) != "audio" || c.Config.File != "a.wav" || c.Config.Language != "en" {
+		t.Errorf("first call = %+v, want the audio, file and language passed in", c)
+	}
+}
+
+func TestMockTranscriberErr(t *testing.T) {
+	errBoom := errors.New("boom")
+	m := &whispertest.MockTranscriber{Err: errBoom}
+	if _, err := summarize(context.Background(), m, "audio"); !errors.Is(err, errBoom) {
+		t.Errorf("summarize() error = %v, want %v", err, errBoom)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := summarize(ctx, whispertest.NewMockTranscriber(), "audio"); !errors.Is(err, context.Canceled) {
+		t.Errorf("summarize() with a canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestMockTranscriberFileAndURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "talk.mp3")
+	if err := os.WriteFile(path, []byte("mp3"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := whispertest.NewMockTranscriber()
+	if _, err := m.TranscribeFileContext(context.Background(), path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.TranscribeURL(context.Background(), "https://example.com/audio/clip.ogg?sig=1"); err != nil {
+		t.Fatal(err)
+	}
+	calls := m.Calls()
+	if calls[0].Config.File != "talk.mp3" || string(calls[0].Audio) != "mp3" {
+		t.Errorf("TranscribeFileContext recorded %+v, want talk.mp3 and its contents", calls[0])
+	}
+	if calls[1].Config.File != "clip.ogg" {
+		t.Errorf("TranscribeURL recorded file %q, want clip.ogg", calls[1].Config.File)
+	}
+}
@@ -0,0 +1,160 @@
+package whispertest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+)
+
+// Response is a canned HTTP response of a Server.
+type Response struct {
+	// Status is the status code; zero means 200.
+	Status int
+	Header http.Header
+	Body   string
+}
+
+// Transcript returns a 200 response with tr encoded as JSON, e.g. a
+// verbose_json transcript.
+func Transcript(tr *models.TranscribeResponse) Response {
+	b, err := json.Marshal(tr)
+	if err != nil {
+		panic(fmt.Sprintf("whispertest: encoding transcript: %v", err))
+	}
+	return Response{Header: http.Header{"Content-Type": {"application/json"}}, Body: string(b)}
+}
+
+// Error returns a response with the given status and an error body in the
+// format of the OpenAI API.
+func Error(status int, message string) Response {
+	b, _ := json.Marshal(map[string]any{"error": map[string]any{"message": message, "type": "server_error"}})
+	return Response{Status: status, Header: http.Header{"Content-Type": {"application/json"}}, Body: string(b)}
+}
+
+// RateLimited returns a 429 response asking the client to retry after the
+// given number of seconds.
+func RateLimited(retryAfter int) Response {
+	r := Error(http.StatusTooManyRequests, "Rate limit reached")
+	r.Header.Set("Retry-After", strconv.Itoa(retryAfter))
+	return r
+}
+
+// Request records a request received by a Server.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	// Fields are the values of the form fields other than the audio.
+	Fields map[string][]string
+	// File is the filename of the uploaded audio, FileField the form field
+	// it was uploaded in and Audio its contents.
+	File      string
+	FileField string
+	Audio     []byte
+	// ContentType is the declared content type of the audio.
+	ContentType string
+}
+
+// Server is an OpenAI-compatible transcription server for tests, built on
+// httptest.Server. Point a client at it with whisper.WithBaseURL(s.URL).
+//
+// It answers requests with its responses in order, repeating the last one
+// once they are exhausted, so that e.g. a 429 followed by a transcript tests
+// a retry. Requests without a model or audio file are rejected with a 400
+// error without consuming a response.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses []Response
+	requests  []Request
+}
+
+// NewServer starts a Server with the given responses. Without responses,
+// every request is answered with an empty verbose_json transcript, which
+// the client only accepts with transcribe.WithAllowEmptyResult. The caller
+// must call Close when done.
+func NewServer(responses ...Response) *Server {
+	s := &Server{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := parseRequest(r)
+	if err != nil {
+		writeResponse(w, Error(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	resp := Transcript(&models.TranscribeResponse{Task: "transcribe", Segments: []models.Segment{}})
+	if len(s.responses) > 0 {
+		resp = s.responses[min(len(s.requests), len(s.responses))-1]
+	}
+	s.mu.Unlock()
+	writeResponse(w, resp)
+}
+
+// parseRequest reads the multipart form of r, requiring a model and an
+// audio file like the API.
+func parseRequest(r *http.Request) (Request, error) {
+	req := Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Fields: map[string][]string{}}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return req, fmt.Errorf("reading form: %v", err)
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return req, fmt.Errorf("reading form: %v", err)
+		}
+		b, err := io.ReadAll(part)
+		if err != nil {
+			return req, fmt.Errorf("reading form: %v", err)
+		}
+		if part.FileName() != "" {
+			req.File, req.FileField, req.Audio = part.FileName(), part.FormName(), b
+			req.ContentType = part.Header.Get("Content-Type")
+			continue
+		}
+		req.Fields[part.FormName()] = append(req.Fields[part.FormName()], string(b))
+	}
+	if len(req.Fields["model"]) == 0 {
+		return req, errors.New("you must provide a model parameter")
+	}
+	if req.File == "" {
+		return req, errors.New("you must provide a file parameter")
+	}
+	return req, nil
+}
+
+func writeResponse(w http.ResponseWriter, resp Response) {
+	for k, vs := range resp.Header {
+		w.Header()[k] = vs
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	io.WriteString(w, resp.Body)
+}
@@ -0,0 +1,90 @@
+package whispertest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper/whispertest"
+	"github.com/akhilsharma90/go-whisper-project/models"
+)
+
+// post sends a transcription form with the given fields and, if file is
+// set, an audio part to srv.
+func post(t *testing.T, srv *whispertest.Server, fields map[string]string, file string) (int, string) {
+	t.Helper()
+	var body bytes.Buffer
+	mp := multipart.NewWriter(&body)
+	for k, v := range fields {
+		mp.WriteField(k, v)
+	}
+	if file != "" {
+		fw, _ := mp.CreateFormFile("file", file)
+		io.WriteString(fw, "audio")
+	}
+	mp.Close()
+	resp, err := http.Post(srv.URL+"/audio/transcriptions", mp.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+func TestServerValidatesForm(t *testing.T) {
+	srv := whispertest.NewServer()
+	defer srv.Close()
+
+	if status, body := post(t, srv, map[string]string{"model": "whisper-1"}, ""); status != http.StatusBadRequest || !strings.Contains(body, "file") {
+		t.Errorf("request without a file: %d %s, want a 400 about the file", status, body)
+	}
+	if status, body := post(t, srv, nil, "a.wav"); status != http.StatusBadRequest || !strings.Contains(body, "model") {
+		t.Errorf("request without a model: %d %s, want a 400 about the model", status, body)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("recorded %d invalid requests, want none", n)
+	}
+
+	status, body := post(t, srv, map[string]string{"model": "whisper-1", "language": "de"}, "a.wav")
+	var tr models.TranscribeResponse
+	if status != http.StatusOK || json.Unmarshal([]byte(body), &tr) != nil || tr.Task != "transcribe" || tr.Segments == nil {
+		t.Errorf("valid request: %d %s, want an empty verbose_json transcript", status, body)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 1 || reqs[0].File != "a.wav" || string(reqs[0].Audio) != "audio" || reqs[0].Fields["language"][0] != "de" {
+		t.Errorf("recorded %+v", reqs)
+	}
+}
+
+func TestServerResponseSequence(t *testing.T) {
+	srv := whispertest.NewServer(
+		whispertest.RateLimited(3),
+		whispertest.Error(http.StatusInternalServerError, "The server had an error"),
+		whispertest.Transcript(&models.TranscribeResponse{Text: "hello"}),
+	)
+	defer srv.Close()
+
+	form := map[string]string{"model": "whisper-1"}
+	want := []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusOK, http.StatusOK}
+	for i, w := range want {
+		if status, body := post(t, srv, form, "a.wav"); status != w {
+			t.Errorf("request %d: %d %s, want %d", i+1, status, body, w)
+		}
+	}
+
+	resp := whispertest.RateLimited(3)
+	if resp.Header.Get("Retry-After") != "3" {
+		t.Errorf("RateLimited(3) Retry-After = %q, want 3", resp.Header.Get("Retry-After"))
+	}
+	var apiErr struct {
+		Error struct{ Message string } `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(whispertest.Error(http.StatusBadRequest, "bad").Body), &apiErr); err != nil || apiErr.Error.Message != "bad" {
+		t.Errorf("Error() body does not have the OpenAI error format: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+)
+
+// Load reads a whisper.Config from a JSON or YAML file, chosen by extension,
+// and applies overrides from the environment.
+func Load(path string) (whisper.Config, error) {
+	var cfg whisper.Config
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(b, &cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &cfg)
+	default:
+		return cfg, fmt.Errorf("unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if err := ApplyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// ApplyEnv overrides cfg with any of the following environment variables
+// that are set and not empty: OPENAI_API_KEY, OPENAI_BASE_URL, WHISPER_MODEL,
+// WHISPER_LANGUAGE, WHISPER_TIMEOUT, WHISPER_RETRIES, WHISPER_PROVIDER,
+// AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_DEPLOYMENT and AZURE_OPENAI_API_VERSION.
+// The API key and base URL are read under cfg.EnvPrefix instead of OPENAI_
+// if it is set.
+func ApplyEnv(cfg *whisper.Config) error {
+	strs := map[string]*string{
+		cfg.EnvName("API_KEY"):     &cfg.APIKey,
+		cfg.EnvName("BASE_URL"):    &cfg.BaseURL,
+		"WHISPER_MODEL":            &cfg.Model,
+		"WHISPER_LANGUAGE":         &cfg.Language,
+		"WHISPER_PROVIDER":         &cfg.Provider,
+		"AZURE_OPENAI_ENDPOINT":    &cfg.Azure.Endpoint,
+		"AZURE_OPENAI_DEPLOYMENT":  &cfg.Azure.Deployment,
+		"AZURE_OPENAI_API_VERSION": &cfg.Azure.APIVersion,
+	}
+	for name, dst := range strs {
+		if v := os.Getenv(name); v != "" {
+			*dst = v
+		}
+	}
+
+	if v := os.Getenv("WHISPER_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("parsing WHISPER_TIMEOUT: %w", err)
+		}
+		cfg.Timeout = whisper.Duration(d)
+	}
+	if v := os.Getenv("WHISPER_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("parsing WHISPER_RETRIES: %w", err)
+		}
+		cfg.Retries = n
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+)
+
+// clearEnv unsets the variables ApplyEnv reads for the duration of the test.
+func clearEnv(t *testing.T) {
+	for _, name := range []string{
+		"OPENAI_API_KEY", "OPENAI_BASE_URL", "WHISPER_MODEL", "WHISPER_LANGUAGE",
+		"WHISPER_TIMEOUT", "WHISPER_RETRIES", "WHISPER_PROVIDER",
+		"AZURE_OPENAI_ENDPOINT", "AZURE_OPENAI_DEPLOYMENT", "AZURE_OPENAI_API_VERSION",
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	clearEnv(t)
+	want := whisper.Config{
+		APIKey:   "sk-file",
+		BaseURL:  "https://example.com/v1",
+		Model:    "whisper-1",
+		Language: "de",
+		Timeout:  whisper.Duration(90 * time.Second),
+		Retries:  3,
+		Headers:  map[string]string{"X-Team": "audio"},
+	}
+	files := map[string]string{
+		"whisper.json": `{"api_key":"sk-file","base_url":"https://example.com/v1","model":"whisper-1",
+			"language":"de","timeout":"1m30s","retries":3,"headers":{"X-Team":"audio"}}`,
+		"whisper.yaml": "api_key: sk-file\nbase_url: https://example.com/v1\nmodel: whisper-1\n" +
+			"language: de\ntimeout: 1m30s\nretries: 3\nheaders:\n  X-Team: audio\n",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := Load(writeFile(t, name, content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("Load() = %+v, want %+v", cfg, want)
+			}
+		})
+	}
+}
+
+func TestLoadRoundTrip(t *testing.T) {
+	clearEnv(t)
+	want := whisper.Config{
+		APIKey:    "sk-file",
+		Timeout:   whisper.Duration(1500 * time.Millisecond),
+		Provider:  whisper.ProviderAzure,
+		Azure:     whisper.AzureConfig{Endpoint: "https://res.openai.azure.com", Deployment: "whisper", APIVersion: "2024-06-01"},
+		EnvPrefix: "MYAPP_",
+	}
+	encoders := map[string]func(any) ([]byte, error){
+		"whisper.json": json.Marshal,
+		"whisper.yml":  yaml.Marshal,
+	}
+	for name, encode := range encoders {
+		t.Run(name, func(t *testing.T) {
+			b, err := encode(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(writeFile(t, name, string(b)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("Load(%s) = %+v, want %+v", b, cfg, want)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	clearEnv(t)
+	if _, err := Load(writeFile(t, "whisper.toml", "")); err == nil {
+		t.Error("Load of a .toml file succeeded")
+	}
+	if _, err := Load(writeFile(t, "whisper.json", "{")); err == nil {
+		t.Error("Load of invalid JSON succeeded")
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("Load of a missing file: %v, want a not-exist error", err)
+	}
+}
+
+func TestApplyEnvPrecedence(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("OPENAI_API_KEY", "sk-env")
+	t.Setenv("WHISPER_MODEL", "")
+	t.Setenv("WHISPER_TIMEOUT", "2m")
+	t.Setenv("WHISPER_RETRIES", "5")
+
+	cfg := whisper.Config{APIKey: "sk-file", Model: "whisper-1", Retries: 1}
+	if err := ApplyEnv(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := whisper.Config{APIKey: "sk-env", Model: "whisper-1", Timeout: whisper.Duration(2 * time.Minute), Retries: 5}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("ApplyEnv() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestApplyEnvPrefix(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("OPENAI_API_KEY", "sk-openai")
+	t.Setenv("MYAPP_API_KEY", "sk-myapp")
+	t.Setenv("MYAPP_BASE_URL", "https://myapp.example.com/v1")
+
+	cfg := whisper.Config{EnvPrefix: "MYAPP"}
+	if err := ApplyEnv(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIKey != "sk-myapp" || cfg.BaseURL != "https://myapp.example.com/v1" {
+		t.Errorf("ApplyEnv() = %+v, want the MYAPP_ variables", cfg)
+	}
+}
+
+func TestApplyEnvInvalid(t *testing.T) {
+	for name, value := range map[string]string{"WHISPER_TIMEOUT": "soon", "WHISPER_RETRIES": "many"} {
+		t.Run(name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv(name, value)
+			if err := ApplyEnv(&whisper.Config{}); err == nil {
+				t.Errorf("ApplyEnv with %s=%q succeeded", name, value)
+			}
+		})
+	}
+}
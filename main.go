@@ -1,20 +1,103 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/akhilsharma90/go-whisper-project/api/whisper"
+	"github.com/akhilsharma90/go-whisper-project/models"
+	"github.com/akhilsharma90/go-whisper-project/transcribe"
 )
 
+// responseFormats maps the -format flag to the API response_format.
+var responseFormats = map[string]string{
+	"text": "text",
+	"srt":  "srt",
+	"vtt":  "vtt",
+	"json": "verbose_json",
+}
+
 func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run transcribes the audio given on the command line and writes the result.
+func run() error {
+	file := flag.String("file", "file.m4a", "audio file to transcribe, or - to read it from stdin")
+	filename := flag.String("filename", "", "file name sent for audio read from stdin, e.g. audio.wav; its extension tells the API the format")
+	model := flag.String("model", "", "model to use (default "+whisper.DefaultModel+")")
+	language := flag.String("language", "", "language of the audio as an ISO-639-1 code")
+	format := flag.String("format", "text", "output format: text, srt, vtt or json")
+	output := flag.String("o", "", "write the output to this file instead of stdout")
+	flag.Parse()
+
+	responseFormat, ok := responseFormats[*format]
+	if !ok {
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+
+	if *file == "-" && *filename == "" {
+		return errors.New("-filename is required when reading audio from stdin, e.g. -filename audio.wav")
+	}
+
+	opts := []transcribe.TranscribeOption{transcribe.WithResponseFormat(responseFormat)}
+	if *model != "" {
+		opts = append(opts, transcribe.WithModel(*model))
+	}
+	if *language != "" {
+		opts = append(opts, transcribe.WithLanguage(*language))
+	}
+
 	client := whisper.NewClient(whisper.WithKey(os.Getenv("OPENAI_API_KEY")))
 
-	response, err := client.TranscribeFile("file.m4a")
+	var response *models.TranscribeResponse
+	var err error
+	if *file == "-" {
+		response, err = client.Transcribe(os.Stdin, append(opts, transcribe.WithFile(*filename))...)
+	} else {
+		response, err = client.TranscribeFile(*file, opts...)
+	}
+	if err != nil {
+		return fmt.Errorf("transcribing file: %w", err)
+	}
+
+	if *output == "" {
+		return writeResponse(os.Stdout, response, *format)
+	}
+	f, err := os.Create(*output)
 	if err != nil {
-		log.Fatalf("Error transcribing file: %v", err)
+		return fmt.Errorf("creating output file: %w", err)
 	}
+	if err := writeResponse(f, response, *format); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
 
-	fmt.Printf("Transcription: %s\n", response.Text)
+// writeResponse writes response to out in the given output format.
+func writeResponse(out io.Writer, response *models.TranscribeResponse, format string) error {
+	var err error
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(response)
+	} else {
+		_, err = fmt.Fprintln(out, strings.TrimRight(response.Text, "\n"))
+	}
+	if err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
 }
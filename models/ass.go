@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ASSStyleOptions configures the default style of an ASS export. Zero
+// fields take the defaults: Arial, size 20, white.
+type ASSStyleOptions struct {
+	FontName string
+	FontSize int
+	// Color is the text color as "#RRGGBB".
+	Color string
+}
+
+// assEscaper escapes text for ASS dialogue lines, which treat braces as
+// override blocks and use \N for line breaks.
+var assEscaper = strings.NewReplacer("{", `\{`, "}", `\}`, "\r\n", `\N`, "\n", `\N`)
+
+// ASS renders the segments as a SubStation Alpha (.ass) subtitle script with
+// a single style built from opts. Segments without text are skipped.
+func (tr *TranscribeResponse) ASS(opts ASSStyleOptions) string {
+	if opts.FontName == "" {
+		opts.FontName = "Arial"
+	}
+	if opts.FontSize <= 0 {
+		opts.FontSize = 20
+	}
+
+	var b strings.Builder
+	b.WriteString("[Script Info]\nScriptType: v4.00+\nWrapStyle: 0\nScaledBorderAndShadow: yes\n\n")
+	b.WriteString("[V4+ Styles]\n")
+	b.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, " +
+		"Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, " +
+		"Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	fmt.Fprintf(&b, "Style: Default,%s,%d,%s,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,0,2,10,10,10,1\n\n",
+		opts.FontName, opts.FontSize, assColor(opts.Color))
+	b.WriteString("[Events]\n")
+	b.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, seg := range tr.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n",
+			assTimestamp(seg.StartDuration()), assTimestamp(seg.EndDuration()), assEscaper.Replace(text))
+	}
+	return b.String()
+}
+
+// assTimestamp formats d as H:MM:SS.cc.
+func assTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	cs := d.Round(10*time.Millisecond) / (10 * time.Millisecond)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// assColor converts "#RRGGBB" to the &H00BBGGRR notation of ASS, defaulting
+// to white.
+func assColor(color string) string {
+	hex := strings.TrimPrefix(color, "#")
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b); err != nil || len(hex) != 6 {
+		return "&H00FFFFFF"
+	}
+	return fmt.Sprintf("&H00%02X%02X%02X", b, g, r)
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestASS(t *testing.T) {
+	tr := &TranscribeResponse{Segments: []Segment{
+		seg(0, 1.234, " Hello {there}."),
+		seg(1.234, 2, "  "),
+		seg(3725.5, 3727, " Line one\nline two"),
+	}}
+	out := tr.ASS(ASSStyleOptions{FontName: "Helvetica", FontSize: 28, Color: "#FF8000"})
+
+	sections := []string{"[Script Info]", "[V4+ Styles]", "[Events]"}
+	last := -1
+	for _, s := range sections {
+		i := strings.Index(out, s+"\n")
+		if i < 0 {
+			t.Fatalf("ASS() has no %s section:\n%s", s, out)
+		}
+		if i < last {
+			t.Errorf("%s section out of order:\n%s", s, out)
+		}
+		last = i
+	}
+	if !strings.Contains(out, "\nStyle: Default,Helvetica,28,&H000080FF,") {
+		t.Errorf("ASS() has no style from the options:\n%s", out)
+	}
+
+	var dialogues []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "Dialogue: ") {
+			dialogues = append(dialogues, line)
+		}
+	}
+	want := []string{
+		`Dialogue: 0,0:00:00.00,0:00:01.23,Default,,0,0,0,,Hello \{there\}.`,
+		`Dialogue: 0,1:02:05.50,1:02:07.00,Default,,0,0,0,,Line one\Nline two`,
+	}
+	if strings.Join(dialogues, "\n") != strings.Join(want, "\n") {
+		t.Errorf("dialogue lines:\n%s\nwant:\n%s", strings.Join(dialogues, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestASSDefaults(t *testing.T) {
+	out := (&TranscribeResponse{}).ASS(ASSStyleOptions{Color: "orange"})
+	if !strings.Contains(out, "\nStyle: Default,Arial,20,&H00FFFFFF,") {
+		t.Errorf("ASS() with default options:\n%s", out)
+	}
+	if strings.Contains(out, "Dialogue:") {
+		t.Errorf("ASS() without segments has dialogue lines:\n%s", out)
+	}
+}
+
+func TestASSTimestamp(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0:00:00.00"},
+		{1234 * time.Millisecond, "0:00:01.23"},
+		{1235 * time.Millisecond, "0:00:01.24"},
+		{59*time.Second + 999*time.Millisecond, "0:01:00.00"},
+		{10 * time.Hour, "10:00:00.00"},
+		{-time.Second, "0:00:00.00"},
+	}
+	for _, tt := range tests {
+		if got := assTimestamp(tt.d); got != tt.want {
+			t.Errorf("assTimestamp(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"math"
+	"strings"
+)
+
+// Confidence maps AvgLogprob to a score between 0 and 1: exp(AvgLogprob),
+// the geometric mean of the token probabilities. An avg_logprob of -1, the
+// threshold Whisper itself uses, gives about 0.37.
+func (s Segment) Confidence() float64 {
+	return math.Min(math.Exp(s.AvgLogprob), 1)
+}
+
+// ConfidenceReport summarizes the confidence of a transcript.
+type ConfidenceReport struct {
+	// MeanAvgLogprob is the mean AvgLogprob of the segments.
+	MeanAvgLogprob float64
+	// LowConfidence are the segments whose AvgLogprob is below the
+	// threshold, in order.
+	LowConfidence []LowConfidenceSegment
+	// LowConfidenceFraction is the fraction of the audio covered by the
+	// LowConfidence segments.
+	LowConfidenceFraction float64
+}
+
+// LowConfidenceSegment is a segment flagged by a ConfidenceReport.
+type LowConfidenceSegment struct {
+	// Index is the position of the segment in Segments.
+	Index int
+	Text  string
+	Start float64
+	End   float64
+}
+
+// ConfidenceReport flags the segments whose AvgLogprob is below threshold,
+// e.g. -1, for human review. The fraction of audio they cover is relative
+// to Duration, or to the end of the last segment if Duration is unknown.
+func (tr *TranscribeResponse) ConfidenceReport(threshold float64) ConfidenceReport {
+	var report ConfidenceReport
+	if len(tr.Segments) == 0 {
+		return report
+	}
+	var sum, low, end float64
+	for i, seg := range tr.Segments {
+		sum += seg.AvgLogprob
+		end = math.Max(end, seg.End)
+		if seg.AvgLogprob < threshold {
+			report.LowConfidence = append(report.LowConfidence, LowConfidenceSegment{
+				Index: i,
+				Text:  strings.TrimSpace(seg.Text),
+				Start: seg.Start,
+				End:   seg.End,
+			})
+			low += math.Max(seg.End-seg.Start, 0)
+		}
+	}
+	report.MeanAvgLogprob = sum / float64(len(tr.Segments))
+	total := tr.Duration
+	if total <= 0 {
+		total = end
+	}
+	if total > 0 {
+		report.LowConfidenceFraction = math.Min(low/total, 1)
+	}
+	return report
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestConfidence(t *testing.T) {
+	tests := []struct {
+		avgLogprob, want float64
+	}{
+		{0, 1},
+		{-1, 1 / math.E},
+		{math.Log(0.5), 0.5},
+		{-100, math.Exp(-100)},
+		// Rounding can push the average slightly above 0.
+		{0.01, 1},
+	}
+	for _, tt := range tests {
+		if got := (Segment{AvgLogprob: tt.avgLogprob}).Confidence(); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("Confidence() with avg_logprob %v = %v, want %v", tt.avgLogprob, got, tt.want)
+		}
+	}
+}
+
+func TestConfidenceReport(t *testing.T) {
+	tr := &TranscribeResponse{
+		Duration: 10,
+		Segments: []Segment{
+			{Start: 0, End: 2, Text: " Clear speech.", AvgLogprob: -0.2},
+			{Start: 2, End: 5, Text: " Mumbling.", AvgLogprob: -1.4},
+			{Start: 5, End: 8, Text: " At the threshold.", AvgLogprob: -1},
+			{Start: 8, End: 9, Text: " Noise.", AvgLogprob: -2.4},
+		},
+	}
+	got := tr.ConfidenceReport(-1)
+	want := ConfidenceReport{
+		MeanAvgLogprob: -1.25,
+		LowConfidence: []LowConfidenceSegment{
+			{Index: 1, Text: "Mumbling.", Start: 2, End: 5},
+			{Index: 3, Text: "Noise.", Start: 8, End: 9},
+		},
+		LowConfidenceFraction: 0.4,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ConfidenceReport(-1) = %+v, want %+v", got, want)
+	}
+
+	// Without a duration the fraction is relative to the last segment end.
+	tr.Duration = 0
+	if got := tr.ConfidenceReport(-1); math.Abs(got.LowConfidenceFraction-4.0/9) > 1e-12 {
+		t.Errorf("LowConfidenceFraction without Duration = %v, want 4/9", got.LowConfidenceFraction)
+	}
+	if got := tr.ConfidenceReport(-3); got.LowConfidence != nil || got.LowConfidenceFraction != 0 {
+		t.Errorf("ConfidenceReport(-3) = %+v, want no flagged segments", got)
+	}
+}
+
+func TestConfidenceReportEmpty(t *testing.T) {
+	for _, tr := range []*TranscribeResponse{{}, {Duration: 5, Text: "hello"}} {
+		if got := tr.ConfidenceReport(-1); !reflect.DeepEqual(got, ConfidenceReport{}) {
+			t.Errorf("ConfidenceReport of %+v = %+v, want the zero report", tr, got)
+		}
+	}
+}
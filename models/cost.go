@@ -0,0 +1,15 @@
+package models
+
+import "math"
+
+// Whisper1RatePerMinute is the price of whisper-1 in USD per minute of audio
+// at the time of writing. Rates change; callers should pass the current rate
+// to EstimatedCostUSD.
+const Whisper1RatePerMinute = 0.006
+
+// EstimatedCostUSD estimates the cost of the transcription from Duration at
+// the given rate in USD per minute. Audio is billed per second, rounded to
+// the nearest second. Duration is only reported by verbose_json.
+func (tr *TranscribeResponse) EstimatedCostUSD(ratePerMinute float64) float64 {
+	return math.Round(tr.Duration) / 60 * ratePerMinute
+}
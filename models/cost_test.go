@@ -0,0 +1,30 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimatedCostUSD(t *testing.T) {
+	tests := []struct {
+		duration float64
+		want     float64
+	}{
+		{0, 0},
+		{0.4, 0},
+		{0.5, 0.0001},
+		{1.49, 0.0001},
+		{59.6, 0.006},
+		{60, 0.006},
+		{90.2, 0.009},
+	}
+	for _, tt := range tests {
+		tr := &TranscribeResponse{Duration: tt.duration}
+		if got := tr.EstimatedCostUSD(Whisper1RatePerMinute); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("EstimatedCostUSD() for %vs = %v, want %v", tt.duration, got, tt.want)
+		}
+	}
+	if got := (&TranscribeResponse{Duration: 120}).EstimatedCostUSD(0.02); math.Abs(got-0.04) > 1e-12 {
+		t.Errorf("EstimatedCostUSD(0.02) for 120s = %v, want 0.04", got)
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+// FilterSegments returns a copy of the response keeping only the segments
+// whose NoSpeechProb is at most maxNoSpeechProb and whose AvgLogprob is at
+// least minAvgLogprob, with Text rebuilt from them. Whisper itself treats
+// segments above 0.6 no_speech_prob and below -1.0 avg_logprob as
+// unreliable, which makes those sensible defaults.
+func (tr *TranscribeResponse) FilterSegments(maxNoSpeechProb float64, minAvgLogprob float64) *TranscribeResponse {
+	return tr.FilterSegmentsFunc(func(seg Segment) bool {
+		return seg.NoSpeechProb <= maxNoSpeechProb && seg.AvgLogprob >= minAvgLogprob
+	})
+}
+
+// FilterSegmentsFunc returns a copy of the response keeping only the
+// segments for which keep returns true, with Text rebuilt from them.
+// Top-level words that start within a removed segment are dropped too. The
+// response itself is not modified.
+func (tr *TranscribeResponse) FilterSegmentsFunc(keep func(Segment) bool) *TranscribeResponse {
+	filtered := *tr
+	filtered.Segments = nil
+	var removed []Segment
+	for _, seg := range tr.Segments {
+		if keep(seg) {
+			filtered.Segments = append(filtered.Segments, seg)
+		} else {
+			removed = append(removed, seg)
+		}
+	}
+	filtered.Text = segmentsText(filtered.Segments)
+
+	if len(removed) > 0 && len(tr.Words) > 0 {
+		filtered.Words = nil
+	words:
+		for _, w := range tr.Words {
+			for _, seg := range removed {
+				if w.Start >= seg.Start && w.Start < seg.End {
+					continue words
+				}
+			}
+			filtered.Words = append(filtered.Words, w)
+		}
+	}
+	return &filtered
+}
+
+// noSpeechLogprob is the avg_logprob below which Whisper takes a segment
+// with a high no_speech_prob for silence.
+const noSpeechLogprob = -1.0
+
+// DropNoSpeech returns a predicate for FilterSegmentsFunc that drops segments
+// Whisper likely hallucinated over silence: those whose NoSpeechProb exceeds
+// threshold and whose AvgLogprob is below -1. Whisper itself uses a
+// threshold of 0.6.
+func DropNoSpeech(threshold float64) func(Segment) bool {
+	return func(seg Segment) bool {
+		return seg.NoSpeechProb <= threshold || seg.AvgLogprob >= noSpeechLogprob
+	}
+}
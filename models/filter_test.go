@@ -0,0 +1,78 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func filterFixture() *TranscribeResponse {
+	return &TranscribeResponse{
+		Text: "Hello. Thanks for watching! Bye.",
+		Segments: []Segment{
+			{Start: 0, End: 2, Text: " Hello.", NoSpeechProb: 0.1, AvgLogprob: -0.2},
+			{Start: 2, End: 5, Text: " Thanks for watching!", NoSpeechProb: 0.9, AvgLogprob: -1.4},
+			{Start: 5, End: 6, Text: " Bye.", NoSpeechProb: 0.7, AvgLogprob: -0.3},
+		},
+		Words: []Word{
+			{Word: "Hello.", Start: 0.1, End: 1.5},
+			{Word: "Thanks", Start: 2, End: 3},
+			{Word: "for", Start: 3, End: 3.5},
+			{Word: "watching!", Start: 3.5, End: 4.8},
+			{Word: "Bye.", Start: 5, End: 5.6},
+		},
+	}
+}
+
+func TestFilterSegments(t *testing.T) {
+	tr := filterFixture()
+	got := tr.FilterSegments(0.6, -1)
+	if got.Text != "Hello." {
+		t.Errorf("Text = %q, want %q", got.Text, "Hello.")
+	}
+	if len(got.Segments) != 1 {
+		t.Errorf("kept %d segments, want 1", len(got.Segments))
+	}
+	if !reflect.DeepEqual(tr, filterFixture()) {
+		t.Error("FilterSegments modified the response")
+	}
+}
+
+func TestFilterSegmentsFuncDropsWords(t *testing.T) {
+	got := filterFixture().FilterSegmentsFunc(DropNoSpeech(0.6))
+	if got.Text != "Hello. Bye." {
+		t.Errorf("Text = %q, want %q", got.Text, "Hello. Bye.")
+	}
+	var words []string
+	for _, w := range got.Words {
+		words = append(words, w.Word)
+	}
+	if want := []string{"Hello.", "Bye."}; !reflect.DeepEqual(words, want) {
+		t.Errorf("Words = %q, want %q", words, want)
+	}
+}
+
+func TestFilterSegmentsFuncKeepAll(t *testing.T) {
+	tr := filterFixture()
+	got := tr.FilterSegmentsFunc(func(Segment) bool { return true })
+	if len(got.Segments) != 3 || len(got.Words) != 5 {
+		t.Errorf("kept %d segments and %d words, want 3 and 5", len(got.Segments), len(got.Words))
+	}
+}
+
+func TestDropNoSpeech(t *testing.T) {
+	keep := DropNoSpeech(0.6)
+	tests := []struct {
+		seg  Segment
+		want bool
+	}{
+		{Segment{NoSpeechProb: 0.9, AvgLogprob: -1.5}, false},
+		{Segment{NoSpeechProb: 0.9, AvgLogprob: -0.5}, true},
+		{Segment{NoSpeechProb: 0.6, AvgLogprob: -1.5}, true},
+		{Segment{NoSpeechProb: 0.1, AvgLogprob: -2}, true},
+	}
+	for _, tt := range tests {
+		if got := keep(tt.seg); got != tt.want {
+			t.Errorf("DropNoSpeech(0.6)(%+v) = %v, want %v", tt.seg, got, tt.want)
+		}
+	}
+}
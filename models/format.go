@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TextFormatOptions configures Format.
+type TextFormatOptions struct {
+	// Timestamps prefixes each segment with its start as [mm:ss], or
+	// [h:mm:ss] from the first hour on.
+	Timestamps bool
+	// ParagraphPause, if positive, inserts a blank line between segments
+	// separated by a pause longer than it.
+	ParagraphPause time.Duration
+	// MaxLineWidth, if positive, wraps lines at word boundaries to at most
+	// this many characters. Continuation lines are indented to the text of
+	// the segment; words longer than the width are not broken.
+	MaxLineWidth int
+}
+
+// Format renders the transcript as readable plain text with one segment per
+// line. A response without segments is rendered from its Text.
+func (tr *TranscribeResponse) Format(opts TextFormatOptions) string {
+	segs := tr.Segments
+	if len(segs) == 0 {
+		segs = []Segment{{Text: tr.Text}}
+		opts.Timestamps = false
+	}
+
+	var b strings.Builder
+	var prev *Segment
+	for i := range segs {
+		seg := &segs[i]
+		text := strings.Join(strings.Fields(seg.Text), " ")
+		if text == "" {
+			continue
+		}
+		if prev != nil && opts.ParagraphPause > 0 && seg.StartDuration()-prev.EndDuration() > opts.ParagraphPause {
+			b.WriteString("\n")
+		}
+		prefix := ""
+		if opts.Timestamps {
+			prefix = formatTimestamp(seg.StartDuration()) + " "
+		}
+		for j, line := range wrapText(text, opts.MaxLineWidth-len(prefix)) {
+			if j == 0 {
+				b.WriteString(prefix)
+			} else {
+				b.WriteString(strings.Repeat(" ", len(prefix)))
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+		prev = seg
+	}
+	return b.String()
+}
+
+// formatTimestamp formats d as [mm:ss], or [h:mm:ss] from the first hour on.
+func formatTimestamp(d time.Duration) string {
+	s := int(max(d, 0) / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("[%d:%02d:%02d]", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("[%02d:%02d]", s/60, s%60)
+}
+
+// wrapText splits text into lines of at most width characters at spaces. A
+// non-positive width disables wrapping.
+func wrapText(text string, width int) []string {
+	if width <= 0 {
+		return []string{text}
+	}
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(text) {
+		w := []rune(word)
+		if len(line) > 0 && len(line)+1+len(w) > width {
+			lines = append(lines, string(line))
+			line = nil
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		line = append(line, w...)
+	}
+	return append(lines, string(line))
+}
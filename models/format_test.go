@@ -0,0 +1,60 @@
+package models
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// golden compares got with the golden file testdata/name, rewriting the file
+// instead when the tests run with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n%s", path, got)
+	}
+}
+
+func TestFormatGolden(t *testing.T) {
+	tr := loadFixture(t, "meeting.json")
+	tests := []struct {
+		name string
+		opts TextFormatOptions
+	}{
+		{"plain", TextFormatOptions{}},
+		{"timestamps", TextFormatOptions{Timestamps: true}},
+		{"paragraphs", TextFormatOptions{Timestamps: true, ParagraphPause: 3 * time.Second}},
+		{"wrapped", TextFormatOptions{Timestamps: true, ParagraphPause: 3 * time.Second, MaxLineWidth: 60}},
+		{"wrapped without timestamps", TextFormatOptions{MaxLineWidth: 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			golden(t, "meeting_"+strings.ReplaceAll(tt.name, " ", "_")+".golden", []byte(tr.Format(tt.opts)))
+		})
+	}
+}
+
+func TestFormatWithoutSegments(t *testing.T) {
+	tr := &TranscribeResponse{Text: "  Just the text, as returned by the json response format.  "}
+	got := tr.Format(TextFormatOptions{Timestamps: true, MaxLineWidth: 30})
+	want := "Just the text, as returned by\nthe json response format.\n"
+	if got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
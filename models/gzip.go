@@ -0,0 +1,30 @@
+package models
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// WriteSRTGzip writes the SRT rendering of the segments to w, compressed
+// with gzip.
+func (tr *TranscribeResponse) WriteSRTGzip(w io.Writer) error {
+	return writeGzip(w, tr.writeSRT)
+}
+
+// WriteVTTGzip writes the VTT rendering of the segments to w, compressed
+// with gzip.
+func (tr *TranscribeResponse) WriteVTTGzip(w io.Writer, opts ...VTTOption) error {
+	return writeGzip(w, func(w io.Writer) error {
+		return tr.writeVTT(w, opts)
+	})
+}
+
+// writeGzip streams the output of write through a gzip writer into w. The
+// gzip stream is only completed if write succeeds.
+func writeGzip(w io.Writer, write func(io.Writer) error) error {
+	zw := gzip.NewWriter(w)
+	if err := write(zw); err != nil {
+		return err
+	}
+	return zw.Close()
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+)
+
+func gunzip(t *testing.T, b []byte) string {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("reading the gzip stream: %v", err)
+	}
+	return string(out)
+}
+
+func TestWriteGzip(t *testing.T) {
+	tr := vttFixture()
+
+	var srtGz bytes.Buffer
+	if err := tr.WriteSRTGzip(&srtGz); err != nil {
+		t.Fatal(err)
+	}
+	srt, err := tr.SRT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := gunzip(t, srtGz.Bytes()); got != srt {
+		t.Errorf("gunzipped SRT = %q, want %q", got, srt)
+	}
+
+	var vttGz bytes.Buffer
+	if err := tr.WriteVTTGzip(&vttGz, WithVTTCueIDs(), WithVTTCRLF()); err != nil {
+		t.Fatal(err)
+	}
+	vtt, err := tr.VTT(WithVTTCueIDs(), WithVTTCRLF())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := gunzip(t, vttGz.Bytes()); got != vtt {
+		t.Errorf("gunzipped VTT = %q, want %q", got, vtt)
+	}
+}
+
+func TestWriteGzipNoSegments(t *testing.T) {
+	var buf bytes.Buffer
+	tr := &TranscribeResponse{Text: "text only"}
+	if err := tr.WriteSRTGzip(&buf); !errors.Is(err, ErrNoSegments) {
+		t.Errorf("WriteSRTGzip() = %v, want ErrNoSegments", err)
+	}
+	if err := tr.WriteVTTGzip(&buf); !errors.Is(err, ErrNoSegments) {
+		t.Errorf("WriteVTTGzip() = %v, want ErrNoSegments", err)
+	}
+}
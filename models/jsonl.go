@@ -0,0 +1,21 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSONL returns the segments as JSON Lines: one JSON object per segment,
+// each terminated by a newline.
+func (tr *TranscribeResponse) JSONL() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, seg := range tr.Segments {
+		// Encode terminates each object with a newline.
+		if err := enc.Encode(seg); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONL(t *testing.T) {
+	tr := &TranscribeResponse{Segments: []Segment{
+		{ID: 0, Start: 0, End: 1.5, Text: " Hello <world> & co."},
+		{ID: 1, Start: 1.5, End: 3, Text: " Grüße aus Köln 👋"},
+		{ID: 2, Start: 3, End: 4.25, Text: " 日本語のテキスト"},
+	}}
+	b, err := tr.JSONL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(b, []byte("}\n")) {
+		t.Errorf("JSONL() = %q, want it newline-terminated", b)
+	}
+	if !bytes.Contains(b, []byte("Grüße aus Köln 👋")) || !bytes.Contains(b, []byte("<world> & co.")) {
+		t.Errorf("JSONL() = %s, want the text unescaped", b)
+	}
+
+	var got []Segment
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		var seg Segment
+		if err := json.Unmarshal(sc.Bytes(), &seg); err != nil {
+			t.Fatalf("line %d is not a JSON object: %v", len(got)+1, err)
+		}
+		got = append(got, seg)
+	}
+	if len(got) != len(tr.Segments) {
+		t.Fatalf("JSONL() has %d lines, want %d", len(got), len(tr.Segments))
+	}
+	for i, seg := range got {
+		want := tr.Segments[i]
+		if seg.ID != want.ID || seg.Start != want.Start || seg.End != want.End || seg.Text != want.Text {
+			t.Errorf("line %d = %+v, want %+v", i+1, seg, want)
+		}
+	}
+}
+
+func TestJSONLEmpty(t *testing.T) {
+	b, err := (&TranscribeResponse{Text: "no segments"}).JSONL()
+	if err != nil || len(b) != 0 {
+		t.Errorf("JSONL() without segments = %q, %v; want no output", b, err)
+	}
+}
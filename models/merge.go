@@ -0,0 +1,162 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrLanguageMismatch is returned by Merge when the parts were detected as
+// different languages.
+var ErrLanguageMismatch = errors.New("parts have different languages")
+
+// Merge stitches the transcripts of consecutive chunks of a recording into
+// one. offsets are the positions of the chunks in the recording; each
+// part's segment and word timestamps are shifted by its offset and the
+// segment IDs are renumbered. Texts are joined with single spaces.
+//
+// When chunks were cut with overlap, the words both transcribe are kept
+// only once: the longest run of words ending the previous part and starting
+// the next is dropped from the next part. Parts are taken to overlap when
+// a part extends beyond the next offset, or when their durations are
+// unknown, in which case at least two words must match.
+//
+// Duration is the sum of the part durations less their overlap. If the
+// parts have different languages, the merged response is returned with the
+// language of the first part, together with an error matching
+// ErrLanguageMismatch.
+func Merge(parts []*TranscribeResponse, offsets []time.Duration) (*TranscribeResponse, error) {
+	if len(parts) != len(offsets) {
+		return nil, fmt.Errorf("merge: %d parts but %d offsets", len(parts), len(offsets))
+	}
+	merged := &TranscribeResponse{Segments: []Segment{}}
+	var texts, prevWords []string
+	var languages []string
+	var end time.Duration
+	for i, part := range parts {
+		if part == nil {
+			return nil, fmt.Errorf("merge: part %d is nil", i)
+		}
+		p := part.ShiftAndClamp(offsets[i], 0)
+		if i == 0 {
+			merged.Task, merged.Language = p.Task, p.Language
+		}
+		if p.Language != "" && !containsFold(languages, p.Language) {
+			languages = append(languages, p.Language)
+		}
+
+		words := strings.Fields(p.Text)
+		if i > 0 {
+			known := p.Duration > 0 && parts[i-1].Duration > 0
+			if !known || end > offsets[i] {
+				minWords := 1
+				if !known {
+					minWords = 2
+				}
+				if n := overlapWords(prevWords, words); n >= minWords {
+					words = words[n:]
+					dropLeadingWords(p, n)
+				}
+			}
+		}
+		if len(words) > 0 {
+			texts = append(texts, strings.Join(words, " "))
+		}
+		prevWords = append(prevWords, words...)
+		prevWords = prevWords[max(len(prevWords)-maxOverlapWords, 0):]
+
+		partEnd := offsets[i] + secondsToDuration(p.Duration)
+		merged.Duration += max(partEnd-max(end, offsets[i]), 0).Seconds()
+		end = max(end, partEnd)
+
+		for _, seg := range p.Segments {
+			seg.ID = len(merged.Segments)
+			merged.Segments = append(merged.Segments, seg)
+		}
+		merged.Words = append(merged.Words, p.Words...)
+	}
+	merged.Text = strings.Join(texts, " ")
+	if len(languages) > 1 {
+		return merged, fmt.Errorf("merge: %w: %s", ErrLanguageMismatch, strings.Join(languages, ", "))
+	}
+	return merged, nil
+}
+
+// maxOverlapWords bounds the overlap Merge looks for.
+const maxOverlapWords = 50
+
+// overlapWords returns the length of the longest run of words ending prev
+// and starting next, compared case-insensitively and ignoring punctuation.
+func overlapWords(prev, next []string) int {
+	for n := min(len(prev), len(next), maxOverlapWords); n > 0; n-- {
+		match := true
+		for j := 0; j < n; j++ {
+			if normalizeWord(prev[len(prev)-n+j]) != normalizeWord(next[j]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return n
+		}
+	}
+	return 0
+}
+
+// normalizeWord lowercases w and strips punctuation.
+func normalizeWord(w string) string {
+	return strings.ToLower(strings.TrimFunc(w, func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) }))
+}
+
+// dropLeadingWords removes the first n words from the segments of tr,
+// dropping segments that are left without text, and the word timestamps
+// that end before the remaining audio. Without segments, the first n word
+// timestamps are removed.
+func dropLeadingWords(tr *TranscribeResponse, n int) {
+	if len(tr.Segments) == 0 {
+		tr.Words = tr.Words[min(n, len(tr.Words)):]
+		return
+	}
+	var cut float64
+	remaining := n
+	for len(tr.Segments) > 0 && remaining > 0 {
+		seg := &tr.Segments[0]
+		fields := strings.Fields(seg.Text)
+		if len(fields) <= remaining {
+			remaining -= len(fields)
+			cut = seg.End
+			tr.Segments = tr.Segments[1:]
+			continue
+		}
+		seg.Text = " " + strings.Join(fields[remaining:], " ")
+		if len(seg.Words) == len(fields) {
+			seg.Words = seg.Words[remaining:]
+			seg.Start = seg.Words[0].Start
+		} else if words := tr.segmentWords(*seg); len(words) == len(fields) {
+			seg.Start = words[remaining].Start
+		}
+		remaining = 0
+	}
+	if len(tr.Segments) > 0 {
+		cut = tr.Segments[0].Start
+	}
+	var words []Word
+	for _, w := range tr.Words {
+		if w.End > cut {
+			words = append(words, w)
+		}
+	}
+	tr.Words = words
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func wordTexts(words []Word) []string {
+	var texts []string
+	for _, w := range words {
+		texts = append(texts, w.Word)
+	}
+	return texts
+}
+
+func TestMerge(t *testing.T) {
+	parts := []*TranscribeResponse{
+		{
+			Task: "transcribe", Language: "english", Duration: 12,
+			Text:     "The quick brown fox jumps",
+			Segments: []Segment{{Start: 0, End: 6, Text: " The quick brown"}, {Start: 6, End: 12, Text: " fox jumps"}},
+		},
+		{
+			Task: "transcribe", Language: "english", Duration: 10,
+			Text:     "jumps over the dog.",
+			Segments: []Segment{{Start: 0, End: 4, Text: " jumps over"}, {Start: 4, End: 8, Text: " the dog."}},
+			Words: []Word{
+				{Word: "jumps", Start: 0.2, End: 1.5},
+				{Word: "over", Start: 1.8, End: 3.5},
+				{Word: "the", Start: 4.2, End: 5},
+				{Word: "dog.", Start: 5.3, End: 7},
+			},
+		},
+	}
+	merged, err := Merge(parts, []time.Duration{0, 10 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "The quick brown fox jumps over the dog."; merged.Text != want {
+		t.Errorf("Text = %q, want %q", merged.Text, want)
+	}
+	if merged.Duration != 20 {
+		t.Errorf("Duration = %v, want 20", merged.Duration)
+	}
+	var ids []int
+	for _, seg := range merged.Segments {
+		ids = append(ids, seg.ID)
+	}
+	if want := []int{0, 1, 2, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("segment IDs = %v, want %v", ids, want)
+	}
+	if got := merged.Segments[2]; got.Text != " over" || math.Abs(got.Start-11.8) > 1e-9 {
+		t.Errorf("trimmed segment = %q at %v, want %q at 11.8", got.Text, got.Start, " over")
+	}
+	if got, want := wordTexts(merged.Words), []string{"over", "the", "dog."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Words = %q, want %q", got, want)
+	}
+	// The parts are not modified.
+	if parts[1].Segments[0].Text != " jumps over" || len(parts[1].Words) != 4 {
+		t.Errorf("Merge modified its parts: %+v", parts[1])
+	}
+}
+
+func TestMergeTrimsWordsByTime(t *testing.T) {
+	parts := []*TranscribeResponse{
+		{Duration: 6, Text: "It is a well-known", Segments: []Segment{{Start: 0, End: 6, Text: " It is a well-known"}}},
+		{
+			Duration: 6,
+			Text:     "well-known fact.",
+			Segments: []Segment{{Start: 0, End: 1.5, Text: " well-known"}, {Start: 1.5, End: 4, Text: " fact."}},
+			Words: []Word{
+				{Word: "well", Start: 0, End: 0.6},
+				{Word: "known", Start: 0.7, End: 1.4},
+				{Word: "fact.", Start: 1.6, End: 2.2},
+			},
+		},
+	}
+	merged, err := Merge(parts, []time.Duration{0, 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "It is a well-known fact."; merged.Text != want {
+		t.Errorf("Text = %q, want %q", merged.Text, want)
+	}
+	if got, want := wordTexts(merged.Words), []string{"fact."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Words = %q, want %q", got, want)
+	}
+}
+
+func TestMergeWithoutOverlap(t *testing.T) {
+	parts := []*TranscribeResponse{
+		{Duration: 5, Text: "Hello there.", Segments: []Segment{{Start: 0, End: 5, Text: " Hello there."}}},
+		{Duration: 5, Text: "There we go.", Segments: []Segment{{Start: 0, End: 5, Text: " There we go."}}},
+	}
+	merged, err := Merge(parts, []time.Duration{0, 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hello there. There we go."; merged.Text != want {
+		t.Errorf("Text = %q, want %q", merged.Text, want)
+	}
+	if merged.Segments[1].Start != 5 {
+		t.Errorf("second segment starts at %v, want 5", merged.Segments[1].Start)
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	if _, err := Merge([]*TranscribeResponse{{}}, nil); err == nil {
+		t.Error("Merge with missing offsets succeeded")
+	}
+	if _, err := Merge([]*TranscribeResponse{nil}, []time.Duration{0}); err == nil {
+		t.Error("Merge with a nil part succeeded")
+	}
+
+	parts := []*TranscribeResponse{{Language: "english", Text: "Hello"}, {Language: "german", Text: "Hallo"}}
+	merged, err := Merge(parts, []time.Duration{0, time.Second})
+	if !errors.Is(err, ErrLanguageMismatch) {
+		t.Errorf("Merge of different languages = %v, want ErrLanguageMismatch", err)
+	}
+	if merged == nil || merged.Language != "english" || merged.Text != "Hello Hallo" {
+		t.Errorf("merged = %+v, want the merged response in the first language", merged)
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Meta describes the request that produced a TranscribeResponse.
+type Meta struct {
+	// RequestID is the X-Request-ID sent with the request.
+	RequestID string
+
+	// Cost is the estimated cost of the request when the client tracks costs.
+	Cost float64
+	// CostKnown reports whether Cost was estimated. It is false when the
+	// client does not track costs or the duration of the audio is unknown.
+	CostKnown bool
+
+	// Endpoint is the base URL of the endpoint that served the request.
+	Endpoint string
+
+	// Attempts is the number of requests it took, including retries and
+	// failovers.
+	Attempts int
+
+	// Timings is set when the client collects connection stats.
+	Timings *Timings
+}
+
+// Timings breaks down where the time of a request was spent. DNS, Connect
+// and TLSHandshake are zero when an idle connection was reused.
+type Timings struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	// Upload is the time from obtaining a connection until the request body was written.
+	Upload time.Duration
+	// TimeToFirstByte is the time from the start of the request until the
+	// first response byte, including the upload.
+	TimeToFirstByte time.Duration
+	// ReusedConn reports whether an idle connection was reused.
+	ReusedConn bool
+}
@@ -0,0 +1,39 @@
+package models
+
+import "strings"
+
+// normalizeConfig holds the settings of NormalizeText.
+type normalizeConfig struct {
+	unicode func(string) string
+}
+
+// NormalizeOption configures NormalizeText.
+type NormalizeOption func(*normalizeConfig)
+
+// WithUnicodeNormalization additionally applies form to the text, e.g.
+// norm.NFC.String from golang.org/x/text/unicode/norm. Unicode is left
+// untouched by default.
+func WithUnicodeNormalization(form func(string) string) NormalizeOption {
+	return func(c *normalizeConfig) {
+		c.unicode = form
+	}
+}
+
+// NormalizedText returns Text with surrounding whitespace trimmed and runs of
+// whitespace collapsed to a single space.
+func (tr *TranscribeResponse) NormalizedText(opts ...NormalizeOption) string {
+	return NormalizeText(tr.Text, opts...)
+}
+
+// NormalizeText trims s and collapses runs of whitespace to a single space.
+func NormalizeText(s string, opts ...NormalizeOption) string {
+	var cfg normalizeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	if cfg.unicode != nil {
+		s = cfg.unicode(s)
+	}
+	return s
+}
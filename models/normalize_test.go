@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"  Hello  world. ", "Hello world."},
+		{"\tline one\n\nline  two\n", "line one line two"},
+		{"Cafe\u0301  au lait", "Cafe\u0301 au lait"},
+		{"", ""},
+		{"   ", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeText(tt.in); got != tt.want {
+			t.Errorf("NormalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	tr := &TranscribeResponse{Text: " Double  spaces "}
+	if got := tr.NormalizedText(); got != "Double spaces" {
+		t.Errorf("NormalizedText() = %q, want %q", got, "Double spaces")
+	}
+}
+
+func TestNormalizeTextUnicode(t *testing.T) {
+	// compose stands in for norm.NFC.String.
+	compose := strings.NewReplacer("e\u0301", "\u00e9").Replace
+	tr := &TranscribeResponse{Text: " Cafe\u0301  au lait "}
+	if got, want := tr.NormalizedText(WithUnicodeNormalization(compose)), "Caf\u00e9 au lait"; got != want {
+		t.Errorf("NormalizedText(WithUnicodeNormalization) = %q, want %q", got, want)
+	}
+	if got, want := tr.NormalizedText(WithUnicodeNormalization(nil)), "Cafe\u0301 au lait"; got != want {
+		t.Errorf("NormalizedText(WithUnicodeNormalization(nil)) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Paragraph is a group of consecutive segments.
+type Paragraph struct {
+	Start Timestamp `json:"start"`
+	End   Timestamp `json:"end"`
+	Text  string    `json:"text"`
+}
+
+// paragraphConfig holds the settings of GroupParagraphs.
+type paragraphConfig struct {
+	pause         time.Duration
+	sentencePause time.Duration
+	maxDuration   time.Duration
+}
+
+// ParagraphOption configures GroupParagraphs.
+type ParagraphOption func(*paragraphConfig)
+
+// WithParagraphPause starts a new paragraph after any pause longer than d.
+// It defaults to 1.5s.
+func WithParagraphPause(d time.Duration) ParagraphOption {
+	return func(c *paragraphConfig) {
+		c.pause = d
+	}
+}
+
+// WithSentencePause starts a new paragraph after a segment ending a
+// sentence with ., ! or ? if it is followed by a pause of at least d. It
+// defaults to 0.75s.
+func WithSentencePause(d time.Duration) ParagraphOption {
+	return func(c *paragraphConfig) {
+		c.sentencePause = d
+	}
+}
+
+// WithMaxParagraphDuration starts a new paragraph before a segment that
+// would make the paragraph longer than d. By default paragraphs are not
+// limited.
+func WithMaxParagraphDuration(d time.Duration) ParagraphOption {
+	return func(c *paragraphConfig) {
+		c.maxDuration = d
+	}
+}
+
+// Paragraphs groups the segment texts into paragraphs, starting a new one
+// whenever the pause between two consecutive segments exceeds pauseThreshold.
+func (tr *TranscribeResponse) Paragraphs(pauseThreshold time.Duration) []string {
+	paragraphs := []string{}
+	var current []string
+	for i, seg := range tr.Segments {
+		if i > 0 && secondsToDuration(seg.Start-tr.Segments[i-1].End) > pauseThreshold && len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = nil
+		}
+		if text := strings.TrimSpace(seg.Text); text != "" {
+			current = append(current, text)
+		}
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, strings.Join(current, " "))
+	}
+	return paragraphs
+}
+
+// GroupParagraphs groups consecutive segments into timed paragraphs,
+// breaking at long pauses, at shorter pauses after the end of a sentence,
+// and before a paragraph exceeds the maximum duration. Transcripts without
+// punctuation are only broken at long pauses and at the maximum duration.
+// Segments without text are skipped.
+func (tr *TranscribeResponse) GroupParagraphs(opts ...ParagraphOption) []Paragraph {
+	cfg := paragraphConfig{pause: 1500 * time.Millisecond, sentencePause: 750 * time.Millisecond}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var paragraphs []Paragraph
+	var current []Segment
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, Paragraph{
+				Start: Timestamp(current[0].StartDuration()),
+				End:   Timestamp(current[len(current)-1].EndDuration()),
+				Text:  segmentsText(current),
+			})
+			current = nil
+		}
+	}
+	for _, seg := range tr.Segments {
+		if strings.TrimSpace(seg.Text) == "" {
+			continue
+		}
+		if len(current) > 0 {
+			prev := current[len(current)-1]
+			gap := seg.StartDuration() - prev.EndDuration()
+			switch {
+			case gap > cfg.pause,
+				endsSentence(prev.Text) && gap >= cfg.sentencePause,
+				cfg.maxDuration > 0 && seg.EndDuration()-current[0].StartDuration() > cfg.maxDuration:
+				flush()
+			}
+		}
+		current = append(current, seg)
+	}
+	flush()
+	return paragraphs
+}
+
+// endsSentence reports whether text ends with sentence-ending punctuation,
+// ignoring closing quotes and brackets.
+func endsSentence(text string) bool {
+	text = strings.TrimRight(strings.TrimSpace(text), `"')]»”’`)
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return strings.ContainsRune(".!?。！？", r)
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func seg(start, end float64, text string) Segment {
+	return Segment{Start: start, End: end, Text: text}
+}
+
+func TestParagraphs(t *testing.T) {
+	tr := &TranscribeResponse{Segments: []Segment{
+		seg(0, 2, " Hello there."),
+		seg(2.5, 4, " How are you?"),
+		seg(4, 5, " "),
+		seg(8, 10, " Fine."),
+	}}
+	got := tr.Paragraphs(time.Second)
+	want := []string{"Hello there. How are you?", "Fine."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Paragraphs(1s) = %q, want %q", got, want)
+	}
+	if got := (&TranscribeResponse{}).Paragraphs(time.Second); got == nil || len(got) != 0 {
+		t.Errorf("Paragraphs of an empty transcript = %#v, want an empty slice", got)
+	}
+	single := &TranscribeResponse{Segments: []Segment{seg(3, 4, " Alone. ")}}
+	if got, want := single.Paragraphs(time.Second), []string{"Alone."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Paragraphs of one segment = %q, want %q", got, want)
+	}
+}
+
+func TestGroupParagraphs(t *testing.T) {
+	ts := func(s float64) Timestamp { return Timestamp(secondsToDuration(s)) }
+	tests := []struct {
+		name string
+		segs []Segment
+		opts []ParagraphOption
+		want []Paragraph
+	}{
+		{
+			name: "long pause",
+			segs: []Segment{seg(0, 2, " One"), seg(2.2, 3, " two"), seg(5, 6, " three")},
+			want: []Paragraph{{Start: 0, End: ts(3), Text: "One two"}, {Start: ts(5), End: ts(6), Text: "three"}},
+		},
+		{
+			name: "sentence pause",
+			segs: []Segment{seg(0, 2, " Done."), seg(3, 4, " Next"), seg(4.2, 5, " one.")},
+			want: []Paragraph{{Start: 0, End: ts(2), Text: "Done."}, {Start: ts(3), End: ts(5), Text: "Next one."}},
+		},
+		{
+			name: "sentence pause inside quotes",
+			segs: []Segment{seg(0, 2, ` "Done!"`), seg(3, 4, " Next")},
+			want: []Paragraph{{Start: 0, End: ts(2), Text: `"Done!"`}, {Start: ts(3), End: ts(4), Text: "Next"}},
+		},
+		{
+			name: "no punctuation",
+			segs: []Segment{seg(0, 2, " one"), seg(3, 4, " two"), seg(5, 6, " three")},
+			want: []Paragraph{{Start: 0, End: ts(6), Text: "one two three"}},
+		},
+		{
+			name: "max duration",
+			segs: []Segment{seg(0, 4, " one"), seg(4, 8, " two"), seg(8, 12, " three")},
+			opts: []ParagraphOption{WithMaxParagraphDuration(10 * time.Second)},
+			want: []Paragraph{{Start: 0, End: ts(8), Text: "one two"}, {Start: ts(8), End: ts(12), Text: "three"}},
+		},
+		{
+			name: "custom pauses",
+			segs: []Segment{seg(0, 2, " Done."), seg(3, 4, " next"), seg(4.5, 5, " one")},
+			opts: []ParagraphOption{WithParagraphPause(400 * time.Millisecond), WithSentencePause(2 * time.Second)},
+			want: []Paragraph{{Start: 0, End: ts(2), Text: "Done."}, {Start: ts(3), End: ts(4), Text: "next"}, {Start: ts(4.5), End: ts(5), Text: "one"}},
+		},
+		{
+			name: "empty segments skipped",
+			segs: []Segment{seg(0, 1, " "), seg(1, 2, " one")},
+			want: []Paragraph{{Start: ts(1), End: ts(2), Text: "one"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TranscribeResponse{Segments: tt.segs}
+			if got := tr.GroupParagraphs(tt.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupParagraphs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SegmentAt returns the segment covering position t, i.e. with
+// Start <= t < End, and false if t falls into a gap or outside the
+// transcript. Segments must be ordered by time, as returned by the API.
+func (tr *TranscribeResponse) SegmentAt(t time.Duration) (*Segment, bool) {
+	secs := t.Seconds()
+	i := sort.Search(len(tr.Segments), func(i int) bool { return tr.Segments[i].End > secs })
+	if i == len(tr.Segments) || tr.Segments[i].Start > secs {
+		return nil, false
+	}
+	return &tr.Segments[i], true
+}
+
+// WordAt is like SegmentAt for the word timestamps of the response, which
+// may be nested in the segments.
+func (tr *TranscribeResponse) WordAt(t time.Duration) (*Word, bool) {
+	words := tr.Words
+	if len(words) == 0 {
+		words = tr.AllWords()
+	}
+	secs := t.Seconds()
+	i := sort.Search(len(words), func(i int) bool { return words[i].End > secs })
+	if i == len(words) || words[i].Start > secs {
+		return nil, false
+	}
+	return &words[i], true
+}
+
+// TextBetween returns the text spoken between start and end: the text of
+// the segments overlapping the window, joined with spaces. Segments that
+// only partly overlap it are trimmed to the words within it when word
+// timestamps are available. It returns "" if no segment overlaps the window
+// and an error if end is before start.
+func (tr *TranscribeResponse) TextBetween(start, end time.Duration) (string, error) {
+	if end < start {
+		return "", fmt.Errorf("invalid time range %s-%s: end is before start", start, end)
+	}
+	from, to := start.Seconds(), end.Seconds()
+	overlaps := func(s, e float64) bool { return e > from && s < to }
+
+	var texts []string
+	for _, seg := range tr.Segments {
+		if !overlaps(seg.Start, seg.End) {
+			continue
+		}
+		text := strings.TrimSpace(seg.Text)
+		if seg.Start < from || seg.End > to {
+			if words := tr.segmentWords(seg); len(words) > 0 {
+				var kept []string
+				for _, w := range words {
+					if overlaps(w.Start, w.End) {
+						kept = append(kept, strings.TrimSpace(w.Word))
+					}
+				}
+				text = strings.Join(kept, " ")
+			}
+		}
+		if text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, " "), nil
+}
+
+// segmentWords returns the words of seg, nested in it or taken from the
+// top-level words within its time span.
+func (tr *TranscribeResponse) segmentWords(seg Segment) []Word {
+	if len(seg.Words) > 0 {
+		return seg.Words
+	}
+	var words []Word
+	for _, w := range tr.Words {
+		if w.Start >= seg.Start && w.Start < seg.End {
+			words = append(words, w)
+		}
+	}
+	return words
+}
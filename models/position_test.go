@@ -0,0 +1,124 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSegmentAt(t *testing.T) {
+	tr := &TranscribeResponse{Segments: []Segment{
+		seg(0, 2, " one"),
+		seg(2, 3.5, " two"),
+		seg(5, 6, " three"),
+	}}
+	tests := []struct {
+		at   time.Duration
+		want string // "" means no segment
+	}{
+		{0, " one"},
+		{1999 * time.Millisecond, " one"},
+		{2 * time.Second, " two"},
+		{3499 * time.Millisecond, " two"},
+		{3500 * time.Millisecond, ""},
+		{4 * time.Second, ""},
+		{5 * time.Second, " three"},
+		{6 * time.Second, ""},
+		{-time.Second, ""},
+	}
+	for _, tt := range tests {
+		s, ok := tr.SegmentAt(tt.at)
+		switch {
+		case tt.want == "" && ok:
+			t.Errorf("SegmentAt(%v) = %q, want none", tt.at, s.Text)
+		case tt.want != "" && (!ok || s.Text != tt.want):
+			t.Errorf("SegmentAt(%v) = %v, %v; want %q", tt.at, s, ok, tt.want)
+		}
+	}
+	if _, ok := (&TranscribeResponse{}).SegmentAt(0); ok {
+		t.Error("SegmentAt on an empty transcript found a segment")
+	}
+
+	s, _ := tr.SegmentAt(5 * time.Second)
+	s.Text = " changed"
+	if tr.Segments[2].Text != " changed" {
+		t.Error("SegmentAt did not return a pointer into Segments")
+	}
+}
+
+func TestWordAt(t *testing.T) {
+	words := []Word{{Word: "one", Start: 0, End: 0.5}, {Word: "two", Start: 0.8, End: 1.2}}
+	nested := &TranscribeResponse{Segments: []Segment{{Start: 0, End: 1.2, Words: words}}}
+	flat := &TranscribeResponse{Words: words}
+	tests := []struct {
+		at   time.Duration
+		want string
+	}{
+		{0, "one"},
+		{500 * time.Millisecond, ""},
+		{800 * time.Millisecond, "two"},
+		{1199 * time.Millisecond, "two"},
+		{1200 * time.Millisecond, ""},
+		{-time.Millisecond, ""},
+		{time.Hour, ""},
+	}
+	for name, tr := range map[string]*TranscribeResponse{"nested": nested, "flat": flat} {
+		for _, tt := range tests {
+			w, ok := tr.WordAt(tt.at)
+			switch {
+			case tt.want == "" && ok:
+				t.Errorf("%s: WordAt(%v) = %q, want none", name, tt.at, w.Word)
+			case tt.want != "" && (!ok || w.Word != tt.want):
+				t.Errorf("%s: WordAt(%v) = %v, %v; want %q", name, tt.at, w, ok, tt.want)
+			}
+		}
+	}
+	if _, ok := (&TranscribeResponse{Segments: []Segment{seg(0, 1, " one")}}).WordAt(0); ok {
+		t.Error("WordAt without word timestamps found a word")
+	}
+}
+
+func TestTextBetween(t *testing.T) {
+	tr := &TranscribeResponse{
+		Segments: []Segment{
+			{Start: 0, End: 2, Text: " Hello there.", Words: []Word{
+				{Word: " Hello", Start: 0, End: 0.6},
+				{Word: " there.", Start: 0.7, End: 1.8},
+			}},
+			seg(2, 4, " General Kenobi."),
+			seg(4, 6, " You are a bold one."),
+		},
+		// Top-level words of the last segment.
+		Words: []Word{
+			{Word: "You", Start: 4, End: 4.3},
+			{Word: "are", Start: 4.4, End: 4.6},
+			{Word: "a", Start: 4.7, End: 4.8},
+			{Word: "bold", Start: 4.9, End: 5.3},
+			{Word: "one.", Start: 5.4, End: 5.9},
+		},
+	}
+	ms := time.Millisecond
+	tests := []struct {
+		name       string
+		start, end time.Duration
+		want       string
+	}{
+		{"whole transcript", 0, 6 * time.Second, "Hello there. General Kenobi. You are a bold one."},
+		{"one segment", 2 * time.Second, 4 * time.Second, "General Kenobi."},
+		{"inside nested words", 650 * ms, 1500 * ms, "there."},
+		{"inside top-level words", 4350 * ms, 5 * time.Second, "are a bold"},
+		{"inside a segment without words", 3 * time.Second, 3500 * ms, "General Kenobi."},
+		{"across segments", time.Second, 5 * time.Second, "there. General Kenobi. You are a bold"},
+		{"between words", 610 * ms, 690 * ms, ""},
+		{"empty window", 2 * time.Second, 2 * time.Second, ""},
+		{"after the end", 6 * time.Second, 7 * time.Second, ""},
+	}
+	for _, tt := range tests {
+		got, err := tr.TextBetween(tt.start, tt.end)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: TextBetween(%v, %v) = %q, %v; want %q", tt.name, tt.start, tt.end, got, err, tt.want)
+		}
+	}
+	if got, err := tr.TextBetween(2*time.Second, time.Second); err == nil {
+		t.Errorf("TextBetween with end before start = %q, want an error", got)
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "strings"
+
+// WordsPerMinute returns the speaking rate over the whole audio: the number
+// of whitespace-separated words in Text per minute of Duration. It is 0 if
+// the duration is unknown.
+func (tr *TranscribeResponse) WordsPerMinute() float64 {
+	if tr.Duration <= 0 {
+		return 0
+	}
+	return float64(len(strings.Fields(tr.Text))) / (tr.Duration / 60)
+}
+
+// ArticulationRate is like WordsPerMinute but only counts the time spent
+// speaking, i.e. the sum of the word durations, so pauses do not lower the
+// rate. It requires word timestamps and is 0 without them.
+func (tr *TranscribeResponse) ArticulationRate() float64 {
+	words := tr.AllWords()
+	var speaking float64
+	for _, w := range words {
+		if w.End > w.Start {
+			speaking += w.End - w.Start
+		}
+	}
+	if speaking <= 0 {
+		return 0
+	}
+	return float64(len(words)) / (speaking / 60)
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWordsPerMinute(t *testing.T) {
+	tests := []struct {
+		text     string
+		duration float64
+		want     float64
+	}{
+		{"one two three four five six", 3, 120},
+		{"  spaced\tout\nwords  ", 90, 2},
+		{"one two", 0, 0},
+		{"", 60, 0},
+	}
+	for _, tt := range tests {
+		tr := &TranscribeResponse{Text: tt.text, Duration: tt.duration}
+		if got := tr.WordsPerMinute(); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("WordsPerMinute(%q over %vs) = %v, want %v", tt.text, tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestArticulationRate(t *testing.T) {
+	// Four words spoken for 0.5s each, with a long pause in between.
+	words := []Word{
+		{Word: "one", Start: 0, End: 0.5},
+		{Word: "two", Start: 0.5, End: 1},
+		{Word: "three", Start: 10, End: 10.5},
+		{Word: "four", Start: 10.5, End: 11},
+	}
+	tr := &TranscribeResponse{Text: "one two three four", Duration: 12, Words: words}
+	if got := tr.ArticulationRate(); math.Abs(got-120) > 1e-9 {
+		t.Errorf("ArticulationRate() = %v, want 120", got)
+	}
+	if got := tr.WordsPerMinute(); math.Abs(got-20) > 1e-9 {
+		t.Errorf("WordsPerMinute() = %v, want 20", got)
+	}
+	nested := &TranscribeResponse{Segments: []Segment{{Start: 0, End: 11, Words: words}}}
+	if got := nested.ArticulationRate(); math.Abs(got-120) > 1e-9 {
+		t.Errorf("ArticulationRate() with nested words = %v, want 120", got)
+	}
+	if got := (&TranscribeResponse{Text: "no words", Duration: 5}).ArticulationRate(); got != 0 {
+		t.Errorf("ArticulationRate() without word timestamps = %v, want 0", got)
+	}
+}
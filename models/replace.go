@@ -0,0 +1,142 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrReplacementConflict is returned for replacements with keys that differ
+// only in case, since matches of either would be ambiguous.
+var ErrReplacementConflict = errors.New("replacement keys differ only in case")
+
+// ApplyReplacements replaces whole words and phrases in the segment texts,
+// e.g. from a glossary of corrections, and rebuilds Text from the segments.
+// Keys are matched case-insensitively and only at word boundaries, so that
+// replacing "gonna" leaves "gonnad" untouched. A response without segments
+// has the replacements applied to Text. The response is modified in place,
+// unless two keys differ only in case, which is reported as
+// ErrReplacementConflict.
+func (tr *TranscribeResponse) ApplyReplacements(replacements map[string]string) error {
+	r, err := newReplacer(replacements)
+	if r == nil || err != nil {
+		return err
+	}
+	if len(tr.Segments) == 0 {
+		tr.Text = r.replace(tr.Text)
+		return nil
+	}
+	for i := range tr.Segments {
+		tr.Segments[i].Text = r.replace(tr.Segments[i].Text)
+	}
+	tr.Text = segmentsText(tr.Segments)
+	return nil
+}
+
+// replacement is a key of a replacer and its value.
+type replacement struct {
+	key, value string
+}
+
+// replacer replaces whole-word matches of its keys.
+type replacer struct {
+	// replacements are ordered by decreasing key length, so that phrases win
+	// over the words they contain.
+	replacements []replacement
+}
+
+// newReplacer returns a replacer for replacements, or nil if there are none.
+func newReplacer(replacements map[string]string) (*replacer, error) {
+	r := &replacer{}
+	for k, v := range replacements {
+		if k != "" {
+			r.replacements = append(r.replacements, replacement{k, v})
+		}
+	}
+	if len(r.replacements) == 0 {
+		return nil, nil
+	}
+	slices.SortFunc(r.replacements, func(a, b replacement) int {
+		if len(a.key) != len(b.key) {
+			return len(b.key) - len(a.key)
+		}
+		return strings.Compare(a.key, b.key)
+	})
+	for i, a := range r.replacements {
+		for _, b := range r.replacements[i+1:] {
+			if strings.EqualFold(a.key, b.key) {
+				return nil, fmt.Errorf("%w: %q and %q", ErrReplacementConflict, a.key, b.key)
+			}
+		}
+	}
+	return r, nil
+}
+
+// replace replaces the matches of the keys in s. At each position the
+// longest key that matches at word boundaries wins, so a key that matches
+// only inside a word does not hide a shorter or overlapping one.
+func (r *replacer) replace(s string) string {
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(s); {
+		if n, value, ok := r.match(s, i); ok {
+			b.WriteString(s[last:i])
+			b.WriteString(value)
+			i += n
+			last = i
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// match returns the length and value of the longest key matching s at i as a
+// whole word.
+func (r *replacer) match(s string, i int) (int, string, bool) {
+	if !wordBoundary(s, i) {
+		return 0, "", false
+	}
+	for _, rep := range r.replacements {
+		if n, ok := prefixFold(s[i:], rep.key); ok && wordBoundary(s, i+n) {
+			return n, rep.value, true
+		}
+	}
+	return 0, "", false
+}
+
+// prefixFold reports whether s starts with prefix under Unicode case folding
+// and returns the length of the matching part of s.
+func prefixFold(s, prefix string) (int, bool) {
+	n := 0
+	for _, pr := range prefix {
+		if n >= len(s) {
+			return 0, false
+		}
+		sr, size := utf8.DecodeRuneInString(s[n:])
+		if !strings.EqualFold(string(sr), string(pr)) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
+
+// wordBoundary reports whether position i of s is not inside a word.
+func wordBoundary(s string, i int) bool {
+	before, _ := utf8.DecodeLastRuneInString(s[:i])
+	after, _ := utf8.DecodeRuneInString(s[i:])
+	return !isWordRune(before) || !isWordRune(after)
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == '\'')
+}
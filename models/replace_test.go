@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyReplacements(t *testing.T) {
+	tests := []struct {
+		name         string
+		replacements map[string]string
+		text, want   string
+	}{
+		{"whole word", map[string]string{"gonna": "going to"}, "I'm gonna go, gonnad.", "I'm going to go, gonnad."},
+		{"case-insensitive", map[string]string{"kubernetes": "Kubernetes"}, "KUBERNETES and kubernetes", "Kubernetes and Kubernetes"},
+		{"phrase before word", map[string]string{"new york": "New York", "york": "Yorkshire"}, "new york and york", "New York and Yorkshire"},
+		{"shorter key when the longer is inside a word", map[string]string{"new york": "NYC", "new": "New"}, "new yorker", "New yorker"},
+		{"overlapping match after a skipped one", map[string]string{"x y": "A", "yz y": "B"}, "x yz y", "x B"},
+		{"punctuation keys", map[string]string{"c plus plus": "C++"}, "I write c plus plus.", "I write C++."},
+		{"unicode folding", map[string]string{"straße": "Strasse"}, "STRAßE", "Strasse"},
+		{"empty key ignored", map[string]string{"": "x"}, "text", "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &TranscribeResponse{Text: tt.text}
+			if err := tr.ApplyReplacements(tt.replacements); err != nil {
+				t.Fatal(err)
+			}
+			if tr.Text != tt.want {
+				t.Errorf("Text = %q, want %q", tr.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyReplacementsSegments(t *testing.T) {
+	tr := &TranscribeResponse{
+		Text:     "gonna win. we gonna",
+		Segments: []Segment{{Text: " gonna win."}, {Text: " we gonna"}},
+	}
+	if err := tr.ApplyReplacements(map[string]string{"gonna": "going to"}); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Segments[0].Text != " going to win." || tr.Segments[1].Text != " we going to" {
+		t.Errorf("Segments = %+v", tr.Segments)
+	}
+	if want := "going to win. we going to"; tr.Text != want {
+		t.Errorf("Text = %q, want %q", tr.Text, want)
+	}
+}
+
+func TestApplyReplacementsConflict(t *testing.T) {
+	tr := &TranscribeResponse{Text: "go Go"}
+	err := tr.ApplyReplacements(map[string]string{"go": "Go", "GO": "golang"})
+	if !errors.Is(err, ErrReplacementConflict) {
+		t.Errorf("ApplyReplacements() = %v, want ErrReplacementConflict", err)
+	}
+	if tr.Text != "go Go" {
+		t.Errorf("Text = %q, want it unmodified", tr.Text)
+	}
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Hit is a match of a Search.
+type Hit struct {
+	// Segment is the index of the segment in Segments.
+	Segment int
+	// Start and End are the timestamps of the matched words in seconds, or
+	// of the whole segment when the response has no word timestamps.
+	Start float64
+	End   float64
+	// Text is the matched text.
+	Text string
+	// Context is the matched text with up to the configured number of bytes
+	// of the segment text around it, cut at character boundaries.
+	Context string
+}
+
+// searchConfig holds the settings of a Search.
+type searchConfig struct {
+	regexp        bool
+	caseSensitive bool
+	context       int
+}
+
+// SearchOption configures a Search.
+type SearchOption func(*searchConfig)
+
+// WithRegexp interprets the query as a regular expression in the syntax of
+// the regexp package.
+func WithRegexp() SearchOption {
+	return func(c *searchConfig) {
+		c.regexp = true
+	}
+}
+
+// WithCaseSensitive matches the query case-sensitively.
+func WithCaseSensitive() SearchOption {
+	return func(c *searchConfig) {
+		c.caseSensitive = true
+	}
+}
+
+// WithSearchContext sets the number of bytes of context around a match.
+// It defaults to 40.
+func WithSearchContext(n int) SearchOption {
+	return func(c *searchConfig) {
+		c.context = n
+	}
+}
+
+// Search returns the matches of query in the segment texts, in order. It
+// matches case-insensitively unless WithCaseSensitive is given. An empty
+// query or an invalid regular expression matches nothing.
+func (tr *TranscribeResponse) Search(query string, opts ...SearchOption) []Hit {
+	cfg := searchConfig{context: 40}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if query == "" {
+		return nil
+	}
+	if !cfg.regexp {
+		query = regexp.QuoteMeta(query)
+	}
+	if !cfg.caseSensitive {
+		query = "(?i)" + query
+	}
+	re, err := regexp.Compile(query)
+	if err != nil {
+		return nil
+	}
+
+	var hits []Hit
+	for i, seg := range tr.Segments {
+		text := seg.Text
+		matches := re.FindAllStringIndex(text, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		spans := wordSpans(text, tr.segmentWords(seg))
+		for _, m := range matches {
+			if m[0] == m[1] {
+				continue
+			}
+			hit := Hit{
+				Segment: i,
+				Start:   seg.Start,
+				End:     seg.End,
+				Text:    text[m[0]:m[1]],
+				Context: strings.TrimSpace(text[runeBoundary(text, m[0]-cfg.context):runeBoundary(text, m[1]+cfg.context)]),
+			}
+			first := true
+			for _, sp := range spans {
+				if sp.end > m[0] && sp.start < m[1] {
+					if first {
+						hit.Start = sp.word.Start
+						first = false
+					}
+					hit.End = sp.word.End
+				}
+			}
+			hits = append(hits, hit)
+		}
+	}
+	return hits
+}
+
+// wordSpan is the position of a word in a segment text.
+type wordSpan struct {
+	start, end int
+	word       Word
+}
+
+// wordSpans locates words in text, in order. Words that cannot be found
+// are left out.
+func wordSpans(text string, words []Word) []wordSpan {
+	var spans []wordSpan
+	pos := 0
+	for _, w := range words {
+		word := strings.TrimSpace(w.Word)
+		if word == "" {
+			continue
+		}
+		i := strings.Index(text[pos:], word)
+		if i < 0 {
+			continue
+		}
+		start := pos + i
+		pos = start + len(word)
+		spans = append(spans, wordSpan{start: start, end: pos, word: w})
+	}
+	return spans
+}
+
+// runeBoundary clamps i to text and moves it back to the start of a rune.
+func runeBoundary(text string, i int) int {
+	i = min(max(i, 0), len(text))
+	for i > 0 && i < len(text) && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
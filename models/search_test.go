@@ -0,0 +1,99 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func searchFixture() *TranscribeResponse {
+	return &TranscribeResponse{
+		Segments: []Segment{
+			{Start: 0, End: 4, Text: " The quick brown fox jumps.", Words: []Word{
+				{Word: "The", Start: 0, End: 0.4},
+				{Word: " quick", Start: 0.5, End: 0.9},
+				{Word: " brown", Start: 1, End: 1.4},
+				{Word: " fox", Start: 1.5, End: 2},
+				{Word: " jumps.", Start: 2.1, End: 3},
+			}},
+			{Start: 4, End: 8, Text: " THE END of the fox?"},
+			{Start: 8, End: 10, Text: " Café crème brûlée"},
+		},
+		// Top-level words of the second segment.
+		Words: []Word{
+			{Word: "THE", Start: 4.1, End: 4.4},
+			{Word: "END", Start: 4.5, End: 5},
+			{Word: "of", Start: 5.1, End: 5.3},
+			{Word: "the", Start: 5.4, End: 5.6},
+			{Word: "fox", Start: 5.7, End: 6.5},
+		},
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  []SearchOption
+		want  []Hit
+	}{
+		{"case-insensitive", "the", nil, []Hit{
+			{Segment: 0, Start: 0, End: 0.4, Text: "The"},
+			{Segment: 1, Start: 4.1, End: 4.4, Text: "THE"},
+			{Segment: 1, Start: 5.4, End: 5.6, Text: "the"},
+		}},
+		{"case-sensitive", "the", []SearchOption{WithCaseSensitive()}, []Hit{
+			{Segment: 1, Start: 5.4, End: 5.6, Text: "the"},
+		}},
+		{"across words", "BROWN FOX", nil, []Hit{{Segment: 0, Start: 1, End: 2, Text: "brown fox"}}},
+		{"inside a word", "ump", nil, []Hit{{Segment: 0, Start: 2.1, End: 3, Text: "ump"}}},
+		{"literal", "fox?", nil, []Hit{{Segment: 1, Start: 5.7, End: 6.5, Text: "fox?"}}},
+		{"regexp", `\bj\w+|e\w+d`, []SearchOption{WithRegexp()}, []Hit{
+			{Segment: 0, Start: 2.1, End: 3, Text: "jumps"},
+			{Segment: 1, Start: 4.5, End: 5, Text: "END"},
+		}},
+		{"no word timestamps", "CRÈME", nil, []Hit{{Segment: 2, Start: 8, End: 10, Text: "crème"}}},
+		{"no match", "cat", nil, nil},
+		{"empty query", "", nil, nil},
+		{"invalid regexp", "(", []SearchOption{WithRegexp()}, nil},
+		{"empty matches", "x*", []SearchOption{WithRegexp(), WithCaseSensitive()}, []Hit{
+			{Segment: 0, Start: 1.5, End: 2, Text: "x"},
+			{Segment: 1, Start: 5.7, End: 6.5, Text: "x"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := searchFixture().Search(tt.query, append(tt.opts, WithSearchContext(0))...)
+			for i := range got {
+				got[i].Context = ""
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchContext(t *testing.T) {
+	tests := []struct {
+		query string
+		n     int
+		want  string
+	}{
+		{"brown", 4, "ick brown fox"},
+		{"brown", 0, "brown"},
+		{"quick", 40, "The quick brown fox jumps."},
+		// Byte offsets inside multi-byte characters move back to their start.
+		{"crème", 2, "é crème b"},
+		{"crème", 3, "é crème br"},
+		{"brûlée", 6, "rème brûlée"},
+	}
+	for _, tt := range tests {
+		hits := searchFixture().Search(tt.query, WithSearchContext(tt.n))
+		if len(hits) != 1 || hits[0].Context != tt.want {
+			t.Errorf("Search(%q) with %d bytes of context = %+v, want context %q", tt.query, tt.n, hits, tt.want)
+		}
+	}
+	if hits := searchFixture().Search("jumps"); len(hits) != 1 || hits[0].Context != "The quick brown fox jumps." {
+		t.Errorf("Search() default context = %+v, want the whole segment", hits)
+	}
+}
@@ -1,15 +1,56 @@
 package models
 
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Segment is a segment of a verbose_json response. Fields the API adds
+// later are ignored when decoding.
 type Segment struct {
-	ID               int     `json:"id"`
-	Seek             int     `json:"seek"`
-	Start            float64 `json:"start"`
-	End              float64 `json:"end"`
-	Text             string  `json:"text"`
-	Tokens           []int   `json:"tokens"`
-	Temperature      float64 `json:"temperature"`
-	AvgLogprob       float64 `json:"avg_logprob"`
+	ID int `json:"id"`
+	// Seek is the offset of the segment's window, in audio frames.
+	Seek int `json:"seek"`
+	// Start and End are the segment's timestamps in seconds. StartDuration
+	// and EndDuration return them as a time.Duration.
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+	// Tokens are the IDs of the text tokens.
+	Tokens []int `json:"tokens"`
+	// Temperature is the sampling temperature the segment was decoded with;
+	// it is raised when decoding at a lower temperature failed.
+	Temperature float64 `json:"temperature"`
+	// AvgLogprob is the average log probability of the tokens. Values below
+	// -1 indicate a low confidence.
+	AvgLogprob float64 `json:"avg_logprob"`
+	// CompressionRatio is the gzip compression ratio of the text. Values
+	// above 2.4 indicate repetitive, likely hallucinated text.
 	CompressionRatio float64 `json:"compression_ratio"`
-	NoSpeechProb     float64 `json:"no_speech_prob"`
-	Transient        bool    `json:"transient"`
+	// NoSpeechProb is the probability that the segment contains no speech.
+	NoSpeechProb float64 `json:"no_speech_prob"`
+	Transient    bool    `json:"transient"`
+	// Words are the words of the segment, for servers that nest them in
+	// the segments. Use TranscribeResponse.AllWords to handle both shapes.
+	Words []Word `json:"words,omitempty"`
+	// Speaker is the speaker label returned by backends that diarize. It is
+	// empty for OpenAI responses.
+	Speaker string `json:"speaker,omitempty"`
+}
+
+// secondsToDuration converts the API's float seconds to a time.Duration.
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
+
+// segmentsText joins the trimmed, non-empty texts of segs with single spaces.
+func segmentsText(segs []Segment) string {
+	var texts []string
+	for _, seg := range segs {
+		if text := strings.TrimSpace(seg.Text); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, " ")
 }
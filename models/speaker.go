@@ -0,0 +1,12 @@
+package models
+
+// BySpeaker groups the segments by their Speaker label, keeping their order.
+// Segments without a label, e.g. all segments of OpenAI responses, are
+// grouped under "".
+func (tr *TranscribeResponse) BySpeaker() map[string][]Segment {
+	groups := make(map[string][]Segment)
+	for _, seg := range tr.Segments {
+		groups[seg.Speaker] = append(groups[seg.Speaker], seg)
+	}
+	return groups
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestBySpeaker(t *testing.T) {
+	payload := `{"task":"transcribe","text":"Hi. Hello. How are you? Fine.","segments":[
+		{"id":0,"start":0,"end":1,"text":" Hi.","speaker":"SPEAKER_00"},
+		{"id":1,"start":1,"end":2,"text":" Hello.","speaker":"SPEAKER_01"},
+		{"id":2,"start":2,"end":3,"text":" How are you?","speaker":"SPEAKER_00"},
+		{"id":3,"start":3,"end":4,"text":" Fine."}]}`
+	var tr TranscribeResponse
+	if err := json.Unmarshal([]byte(payload), &tr); err != nil {
+		t.Fatal(err)
+	}
+	groups := tr.BySpeaker()
+	texts := make(map[string][]string)
+	for speaker, segs := range groups {
+		for _, s := range segs {
+			texts[speaker] = append(texts[speaker], s.Text)
+		}
+	}
+	want := map[string][]string{
+		"SPEAKER_00": {" Hi.", " How are you?"},
+		"SPEAKER_01": {" Hello."},
+		"":           {" Fine."},
+	}
+	if !reflect.DeepEqual(texts, want) {
+		t.Errorf("BySpeaker() = %q, want %q", texts, want)
+	}
+}
+
+func TestBySpeakerUnlabeled(t *testing.T) {
+	tr := &TranscribeResponse{Segments: []Segment{seg(0, 1, " one"), seg(1, 2, " two")}}
+	groups := tr.BySpeaker()
+	if len(groups) != 1 || len(groups[""]) != 2 {
+		t.Errorf("BySpeaker() = %+v, want both segments under \"\"", groups)
+	}
+	b, err := json.Marshal(tr.Segments[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fields["speaker"]; ok {
+		t.Errorf("segment without a speaker encodes as %s, want no speaker field", b)
+	}
+}
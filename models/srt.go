@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNoSegments is returned when exporting subtitles from a response
+// without segments, e.g. one requested in the json instead of the
+// verbose_json format.
+var ErrNoSegments = errors.New("response has no segments")
+
+// SRT renders the segments as numbered SubRip (.srt) cues. Segments without
+// text or duration are skipped, and a segment overlapping its predecessor
+// starts when the predecessor ends, so that the cues are always valid.
+func (tr *TranscribeResponse) SRT() (string, error) {
+	var b strings.Builder
+	if err := tr.writeSRT(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// writeSRT writes the SRT rendering of the segments to w.
+func (tr *TranscribeResponse) writeSRT(w io.Writer) error {
+	if len(tr.Segments) == 0 {
+		return ErrNoSegments
+	}
+	for i, c := range subtitleCues(tr.Segments) {
+		if _, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", i+1, srtTimestamp(c.start), srtTimestamp(c.end), c.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cue is a subtitle cue built from a segment.
+type cue struct {
+	start, end time.Duration
+	text       string
+	speaker    string
+}
+
+// subtitleCues converts segs to cues with trimmed text, clamping each start
+// to the end of the previous cue and dropping empty and zero-length cues.
+func subtitleCues(segs []Segment) []cue {
+	var cues []cue
+	var prevEnd time.Duration
+	for _, seg := range segs {
+		text := strings.TrimSpace(seg.Text)
+		start, end := max(seg.StartDuration(), prevEnd), seg.EndDuration()
+		if text == "" || end <= start {
+			continue
+		}
+		cues = append(cues, cue{start: start, end: end, text: text, speaker: seg.Speaker})
+		prevEnd = end
+	}
+	return cues
+}
+
+// srtTimestamp formats d as HH:MM:SS,mmm.
+func srtTimestamp(d time.Duration) string {
+	ms := d.Round(time.Millisecond) / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
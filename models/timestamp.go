@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Timestamp is a position in the audio. It is encoded in JSON as float
+// seconds, like the timestamps of the API.
+type Timestamp time.Duration
+
+// Duration returns t as a time.Duration.
+func (t Timestamp) Duration() time.Duration {
+	return time.Duration(t)
+}
+
+// MarshalJSON encodes t as float seconds.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return strconv.AppendFloat(nil, time.Duration(t).Seconds(), 'f', -1, 64), nil
+}
+
+// UnmarshalJSON decodes float seconds.
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	var secs float64
+	if err := json.Unmarshal(b, &secs); err != nil {
+		return err
+	}
+	*t = Timestamp(secondsToDuration(secs))
+	return nil
+}
+
+// StartDuration returns the start of the segment as a time.Duration.
+func (s Segment) StartDuration() time.Duration {
+	return secondsToDuration(s.Start)
+}
+
+// EndDuration returns the end of the segment as a time.Duration.
+func (s Segment) EndDuration() time.Duration {
+	return secondsToDuration(s.End)
+}
+
+// StartDuration returns the start of the word as a time.Duration.
+func (w Word) StartDuration() time.Duration {
+	return secondsToDuration(w.Start)
+}
+
+// EndDuration returns the end of the word as a time.Duration.
+func (w Word) EndDuration() time.Duration {
+	return secondsToDuration(w.End)
+}
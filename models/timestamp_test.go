@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampRoundTrip(t *testing.T) {
+	for _, d := range []time.Duration{
+		0,
+		time.Millisecond,
+		1234 * time.Millisecond,
+		59*time.Minute + 59*time.Second + 999*time.Millisecond,
+		10*time.Hour + 123456789,
+		-1500 * time.Millisecond,
+	} {
+		b, err := json.Marshal(Timestamp(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Timestamp
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", b, err)
+		}
+		if diff := (got.Duration() - d).Abs(); diff >= time.Millisecond {
+			t.Errorf("%v encoded as %s decodes to %v", d, b, got.Duration())
+		}
+	}
+}
+
+func TestTimestampUnmarshal(t *testing.T) {
+	tests := []struct {
+		json string
+		want time.Duration
+	}{
+		{"0", 0},
+		{"1.5", 1500 * time.Millisecond},
+		{"3.319999933242798", 3320 * time.Millisecond},
+		{"8.470000267028809", 8470 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		var got Timestamp
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", tt.json, err)
+		}
+		if diff := (got.Duration() - tt.want).Abs(); diff >= time.Millisecond {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.json, got.Duration(), tt.want)
+		}
+	}
+	var ts Timestamp
+	if err := json.Unmarshal([]byte(`"1.5"`), &ts); err == nil {
+		t.Error("Unmarshal of a string succeeded")
+	}
+}
+
+func TestDurationAccessors(t *testing.T) {
+	s := Segment{Start: 3.319999933242798, End: 8.470000267028809}
+	if d := s.StartDuration() - 3320*time.Millisecond; d.Abs() >= time.Millisecond {
+		t.Errorf("StartDuration() = %v", s.StartDuration())
+	}
+	if d := s.EndDuration() - 8470*time.Millisecond; d.Abs() >= time.Millisecond {
+		t.Errorf("EndDuration() = %v", s.EndDuration())
+	}
+	w := Word{Start: 0.42, End: 1.1}
+	if w.StartDuration() != 420*time.Millisecond || w.EndDuration() != 1100*time.Millisecond {
+		t.Errorf("word durations = %v, %v; want 420ms, 1.1s", w.StartDuration(), w.EndDuration())
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ShiftAndClamp returns a copy of the response with offset added to all
+// segment and word timestamps, e.g. to map them back onto the original
+// recording after leading silence was trimmed. Timestamps beyond
+// totalDuration are clamped to it; a non-positive totalDuration disables
+// the clamping.
+func (tr *TranscribeResponse) ShiftAndClamp(offset time.Duration, totalDuration time.Duration) *TranscribeResponse {
+	limit := math.Inf(1)
+	if totalDuration > 0 {
+		limit = totalDuration.Seconds()
+	}
+	secs := offset.Seconds()
+	return tr.mapTimings(func(t float64) float64 { return math.Min(t+secs, limit) })
+}
+
+// Shift returns a copy of the response with delta added to all segment and
+// word timestamps and to Duration, e.g. to fix subtitles made early by an
+// intro trimmed before transcription. Timestamps that would be negative are
+// clamped to zero.
+func (tr *TranscribeResponse) Shift(delta time.Duration) *TranscribeResponse {
+	secs := delta.Seconds()
+	shifted := tr.mapTimings(func(t float64) float64 { return math.Max(t+secs, 0) })
+	shifted.Duration = math.Max(tr.Duration+secs, 0)
+	return shifted
+}
+
+// Scale returns a copy of the response with all segment and word timestamps
+// and Duration multiplied by factor, e.g. to fix subtitles drifting because
+// the audio was resampled at a different speed. factor must be positive.
+func (tr *TranscribeResponse) Scale(factor float64) (*TranscribeResponse, error) {
+	if !(factor > 0) || math.IsInf(factor, 1) {
+		return nil, fmt.Errorf("invalid scale factor %v: must be positive", factor)
+	}
+	scaled := tr.mapTimings(func(t float64) float64 { return math.Max(t*factor, 0) })
+	scaled.Duration = tr.Duration * factor
+	return scaled, nil
+}
+
+// mapTimings returns a copy of the response with fn applied to all segment
+// and word timestamps.
+func (tr *TranscribeResponse) mapTimings(fn func(float64) float64) *TranscribeResponse {
+	c := tr.cloneTimings()
+	for i := range c.Segments {
+		seg := &c.Segments[i]
+		seg.Start, seg.End = fn(seg.Start), fn(seg.End)
+		for j := range seg.Words {
+			seg.Words[j].Start, seg.Words[j].End = fn(seg.Words[j].Start), fn(seg.Words[j].End)
+		}
+	}
+	for i := range c.Words {
+		c.Words[i].Start, c.Words[i].End = fn(c.Words[i].Start), fn(c.Words[i].End)
+	}
+	return c
+}
+
+// cloneTimings returns a copy of the response whose segments and words can
+// be modified without affecting tr.
+func (tr *TranscribeResponse) cloneTimings() *TranscribeResponse {
+	c := *tr
+	c.Segments = append([]Segment(nil), tr.Segments...)
+	for i := range c.Segments {
+		c.Segments[i].Words = append([]Word(nil), c.Segments[i].Words...)
+	}
+	c.Words = append([]Word(nil), tr.Words...)
+	return &c
+}
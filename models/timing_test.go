@@ -0,0 +1,126 @@
+package models
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func timingFixture() *TranscribeResponse {
+	return &TranscribeResponse{
+		Duration: 9,
+		Segments: []Segment{
+			{Start: 0, End: 4, Text: " one", Words: []Word{{Word: "one", Start: 0.5, End: 3.5}}},
+			{Start: 4, End: 9, Text: " two"},
+		},
+		Words: []Word{{Word: "one", Start: 0.5, End: 3.5}, {Word: "two", Start: 4.5, End: 8.8}},
+	}
+}
+
+// timings lists the segment, nested word and top-level word timestamps of tr.
+func timings(tr *TranscribeResponse) []float64 {
+	var ts []float64
+	for _, s := range tr.Segments {
+		ts = append(ts, s.Start, s.End)
+		for _, w := range s.Words {
+			ts = append(ts, w.Start, w.End)
+		}
+	}
+	for _, w := range tr.Words {
+		ts = append(ts, w.Start, w.End)
+	}
+	return ts
+}
+
+func TestShiftAndClamp(t *testing.T) {
+	tests := []struct {
+		name          string
+		offset, total time.Duration
+		want          []float64
+	}{
+		{"offset", 2 * time.Second, 0, []float64{2, 6, 2.5, 5.5, 6, 11, 2.5, 5.5, 6.5, 10.8}},
+		{"clamped", 2 * time.Second, 10 * time.Second, []float64{2, 6, 2.5, 5.5, 6, 10, 2.5, 5.5, 6.5, 10}},
+		{"clamp only", 0, 8 * time.Second, []float64{0, 4, 0.5, 3.5, 4, 8, 0.5, 3.5, 4.5, 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := timingFixture()
+			got := tr.ShiftAndClamp(tt.offset, tt.total)
+			if ts := timings(got); !reflect.DeepEqual(ts, tt.want) {
+				t.Errorf("timestamps = %v, want %v", ts, tt.want)
+			}
+			if got.Duration != 9 {
+				t.Errorf("Duration = %v, want 9 unchanged", got.Duration)
+			}
+			if !reflect.DeepEqual(tr, timingFixture()) {
+				t.Error("ShiftAndClamp modified the response")
+			}
+		})
+	}
+}
+
+// approxEqual reports whether a and b hold the same timestamps up to
+// floating-point rounding.
+func approxEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestShift(t *testing.T) {
+	tests := []struct {
+		name     string
+		delta    time.Duration
+		want     []float64
+		duration float64
+	}{
+		{"later", 1500 * time.Millisecond, []float64{1.5, 5.5, 2, 5, 5.5, 10.5, 2, 5, 6, 10.3}, 10.5},
+		{"earlier", -1500 * time.Millisecond, []float64{0, 2.5, 0, 2, 2.5, 7.5, 0, 2, 3, 7.3}, 7.5},
+		{"past the end", -10 * time.Second, []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := timingFixture()
+			got := tr.Shift(tt.delta)
+			if ts := timings(got); !approxEqual(ts, tt.want) {
+				t.Errorf("timestamps = %v, want %v", ts, tt.want)
+			}
+			if got.Duration != tt.duration {
+				t.Errorf("Duration = %v, want %v", got.Duration, tt.duration)
+			}
+			if !reflect.DeepEqual(tr, timingFixture()) {
+				t.Error("Shift modified the response")
+			}
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	tr := timingFixture()
+	got, err := tr.Scale(0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts, want := timings(got), []float64{0, 2, 0.25, 1.75, 2, 4.5, 0.25, 1.75, 2.25, 4.4}; !reflect.DeepEqual(ts, want) {
+		t.Errorf("timestamps = %v, want %v", ts, want)
+	}
+	if got.Duration != 4.5 {
+		t.Errorf("Duration = %v, want 4.5", got.Duration)
+	}
+	if !reflect.DeepEqual(tr, timingFixture()) {
+		t.Error("Scale modified the response")
+	}
+
+	for _, factor := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		if _, err := tr.Scale(factor); err == nil {
+			t.Errorf("Scale(%v) succeeded, want an error", factor)
+		}
+	}
+}
@@ -1,10 +1,137 @@
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 // TranscribeResponse represents the response from the Whisper ASR API.
 type TranscribeResponse struct {
 	Task     string    `json:"task"`
 	Language string    `json:"language"`
 	Duration float64   `json:"duration"`
 	Segments []Segment `json:"segments"`
+	Words    []Word    `json:"words,omitempty"`
 	Text     string    `json:"text"`
+
+	// SourceFile is the name the audio was uploaded as, and RequestID the
+	// x-request-id the server assigned to the request. They are set by the
+	// client after decoding and are not part of the API payload.
+	SourceFile string `json:"-"`
+	RequestID  string `json:"-"`
+
+	// Meta holds client-side information about the request that produced
+	// this response. It is not part of the API payload.
+	Meta Meta `json:"-"`
+
+	// Extra holds top-level fields of the payload that TranscribeResponse
+	// does not know about, such as fields added to the API later.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// knownFields are the top-level payload fields decoded into TranscribeResponse.
+var knownFields = []string{"task", "language", "duration", "segments", "words", "text"}
+
+// responseFields has the fields of TranscribeResponse without its JSON
+// methods.
+type responseFields TranscribeResponse
+
+// transcribeResponse is the payload decoded by UnmarshalJSON. Its name
+// appears in decoding errors.
+type transcribeResponse struct {
+	*responseFields
+	// Duration shadows the field of responseFields.
+	Duration json.RawMessage `json:"duration"`
+}
+
+// UnmarshalJSON decodes the payload and collects unknown top-level fields
+// in Extra. It accepts the duration as a number or a string, as some
+// OpenAI-compatible servers return it.
+func (tr *TranscribeResponse) UnmarshalJSON(data []byte) error {
+	aux := transcribeResponse{responseFields: (*responseFields)(tr)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	duration, err := decodeSeconds(aux.Duration)
+	if err != nil {
+		return fmt.Errorf("decoding duration: %w", err)
+	}
+	tr.Duration = duration
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name := range fields {
+		// encoding/json matches field names case-insensitively.
+		if slices.ContainsFunc(knownFields, func(known string) bool { return strings.EqualFold(name, known) }) {
+			delete(fields, name)
+		}
+	}
+	if len(fields) > 0 {
+		tr.Extra = fields
+	} else {
+		tr.Extra = nil
+	}
+	return nil
+}
+
+// MarshalJSON encodes the response like the API, including the fields in
+// Extra, so that a decoded response can be stored and decoded again without
+// losing fields.
+func (tr TranscribeResponse) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(responseFields(tr))
+	if err != nil || len(tr.Extra) == 0 {
+		return b, err
+	}
+	names := make([]string, 0, len(tr.Extra))
+	for name := range tr.Extra {
+		// Known fields are encoded from the struct.
+		if !slices.ContainsFunc(knownFields, func(known string) bool { return strings.EqualFold(name, known) }) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	for _, name := range names {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(',')
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err := json.Compact(buf, tr.Extra[name]); err != nil {
+			return nil, fmt.Errorf("encoding extra field %s: %w", name, err)
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeSeconds decodes a number of seconds given as a JSON number or
+// string. null, an empty string and a missing value decode as zero.
+func decodeSeconds(raw json.RawMessage) (float64, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return 0, err
+		}
+		if s = strings.TrimSpace(s); s == "" {
+			return 0, nil
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+	var secs float64
+	err := json.Unmarshal(raw, &secs)
+	return secs, err
 }
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalExtra(t *testing.T) {
+	payload := `{"task":"transcribe","Language":"english","duration":1.5,"text":"Hi.",
+		"segments":[{"id":0,"start":0,"end":1.5,"text":" Hi."}],
+		"usage":{"type":"duration","seconds":2},"x_model_build":"2024-06"}`
+	var tr TranscribeResponse
+	if err := json.Unmarshal([]byte(payload), &tr); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]json.RawMessage{
+		"usage":         json.RawMessage(`{"type":"duration","seconds":2}`),
+		"x_model_build": json.RawMessage(`"2024-06"`),
+	}
+	if !reflect.DeepEqual(tr.Extra, want) {
+		t.Errorf("Extra = %s, want %s", tr.Extra, want)
+	}
+	if tr.Language != "english" || tr.Duration != 1.5 || len(tr.Segments) != 1 {
+		t.Errorf("known fields not decoded: %+v", tr)
+	}
+}
+
+func TestUnmarshalNoExtra(t *testing.T) {
+	tr := TranscribeResponse{Extra: map[string]json.RawMessage{"stale": json.RawMessage(`1`)}}
+	if err := json.Unmarshal([]byte(`{"text":"Hi."}`), &tr); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Extra != nil {
+		t.Errorf("Extra = %s, want nil for a payload without unknown fields", tr.Extra)
+	}
+}
+
+func TestUnmarshalVerboseJSON(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "openai_verbose.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tr TranscribeResponse
+	if err := json.Unmarshal(b, &tr); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Task != "transcribe" || tr.Language != "english" || tr.Duration != 8.470000267028809 || len(tr.Segments) != 2 {
+		t.Fatalf("decoded %+v", tr)
+	}
+	want := Segment{
+		ID:               1,
+		Seek:             0,
+		Start:            3.319999933242798,
+		End:              8.470000267028809,
+		Text:             " People were swimming in the ocean.",
+		Tokens:           []int{50530, 3432, 645, 11989, 294, 264, 7810, 13, 50788},
+		Temperature:      0.2,
+		AvgLogprob:       -0.4175431132316589,
+		CompressionRatio: 1.2363636493682861,
+		NoSpeechProb:     0.012130411714315414,
+	}
+	if !reflect.DeepEqual(tr.Segments[1], want) {
+		t.Errorf("Segments[1] = %+v, want %+v", tr.Segments[1], want)
+	}
+	if _, ok := tr.Extra["usage"]; !ok {
+		t.Errorf("Extra = %s, want the usage field", tr.Extra)
+	}
+}
+
+func TestUnmarshalBackends(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		task     string
+		language string
+		duration float64
+		segments int
+		extra    []string
+	}{
+		{"openai_verbose.json", "transcribe", "english", 8.470000267028809, 2, []string{"usage"}},
+		{"groq_verbose.json", "transcribe", "English", 3.12, 1, []string{"x_groq"}},
+		// faster-whisper servers omit the task and return the duration as a
+		// string.
+		{"faster_whisper.json", "", "en", 5.25, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tr TranscribeResponse
+			if err := json.Unmarshal(b, &tr); err != nil {
+				t.Fatal(err)
+			}
+			if tr.Task != tt.task || tr.Language != tt.language || tr.Duration != tt.duration || len(tr.Segments) != tt.segments {
+				t.Errorf("decoded task %q, language %q, duration %v, %d segments", tr.Task, tr.Language, tr.Duration, len(tr.Segments))
+			}
+			var extra []string
+			for name := range tr.Extra {
+				extra = append(extra, name)
+			}
+			if !reflect.DeepEqual(extra, tt.extra) {
+				t.Errorf("Extra has %v, want %v", extra, tt.extra)
+			}
+		})
+	}
+}
+
+func TestUnmarshalDuration(t *testing.T) {
+	for payload, want := range map[string]float64{
+		`{"duration":2.5}`:   2.5,
+		`{"duration":"2.5"}`: 2.5,
+		`{"duration":" 7 "}`: 7,
+		`{"duration":""}`:    0,
+		`{"duration":null}`:  0,
+		`{}`:                 0,
+	} {
+		var tr TranscribeResponse
+		if err := json.Unmarshal([]byte(payload), &tr); err != nil || tr.Duration != want {
+			t.Errorf("decoding %s: duration %v, %v, want %v", payload, tr.Duration, err, want)
+		}
+	}
+	for _, payload := range []string{`{"duration":"soon"}`, `{"duration":true}`} {
+		var tr TranscribeResponse
+		if err := json.Unmarshal([]byte(payload), &tr); err == nil {
+			t.Errorf("decoding %s succeeded, want an error", payload)
+		}
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	for _, fixture := range []string{"openai_verbose.json", "groq_verbose.json", "faster_whisper.json"} {
+		t.Run(fixture, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join("testdata", fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tr TranscribeResponse
+			if err := json.Unmarshal(b, &tr); err != nil {
+				t.Fatal(err)
+			}
+			cached, err := json.Marshal(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var reloaded TranscribeResponse
+			if err := json.Unmarshal(cached, &reloaded); err != nil {
+				t.Fatal(err)
+			}
+			// MarshalJSON compacts the extra fields of the indented fixtures.
+			for name, raw := range tr.Extra {
+				var buf bytes.Buffer
+				if err := json.Compact(&buf, raw); err != nil {
+					t.Fatal(err)
+				}
+				tr.Extra[name] = buf.Bytes()
+			}
+			if !reflect.DeepEqual(reloaded, tr) {
+				t.Errorf("reloaded %+v, want %+v", reloaded, tr)
+			}
+			again, err := json.Marshal(reloaded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(cached) {
+				t.Errorf("second encoding differs:\n%s\n%s", again, cached)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// vttConfig holds the settings of a VTT export.
+type vttConfig struct {
+	note           string
+	crlf           bool
+	cueIDs         bool
+	maxCueDuration time.Duration
+}
+
+// VTTOption configures a VTT export.
+type VTTOption func(*vttConfig)
+
+// WithVTTNote adds a NOTE block with the given text after the header.
+func WithVTTNote(note string) VTTOption {
+	return func(c *vttConfig) {
+		c.note = note
+	}
+}
+
+// WithVTTCRLF ends lines with CRLF instead of LF.
+func WithVTTCRLF() VTTOption {
+	return func(c *vttConfig) {
+		c.crlf = true
+	}
+}
+
+// WithVTTCueIDs numbers the cues, starting at 1.
+func WithVTTCueIDs() VTTOption {
+	return func(c *vttConfig) {
+		c.cueIDs = true
+	}
+}
+
+// WithVTTMaxCueDuration splits segments longer than d into cues of equal
+// length, dividing the text between them by words.
+func WithVTTMaxCueDuration(d time.Duration) VTTOption {
+	return func(c *vttConfig) {
+		c.maxCueDuration = d
+	}
+}
+
+// vttEscaper escapes the characters WebVTT reserves in cue text.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// VTT renders the segments as a WebVTT document. Segments are cleaned up
+// like in SRT, and the text of segments with a Speaker label is wrapped in
+// a voice span.
+func (tr *TranscribeResponse) VTT(opts ...VTTOption) (string, error) {
+	var b strings.Builder
+	if err := tr.writeVTT(&b, opts); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// writeVTT writes the VTT rendering of the segments to w.
+func (tr *TranscribeResponse) writeVTT(w io.Writer, opts []VTTOption) error {
+	if len(tr.Segments) == 0 {
+		return ErrNoSegments
+	}
+	var cfg vttConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	nl := "\n"
+	if cfg.crlf {
+		nl = "\r\n"
+	}
+
+	if _, err := io.WriteString(w, "WEBVTT"+nl+nl); err != nil {
+		return err
+	}
+	if note := strings.TrimSpace(cfg.note); note != "" {
+		// A note must not contain "-->" or blank lines.
+		note = strings.ReplaceAll(note, "-->", "->")
+		if _, err := io.WriteString(w, "NOTE"+nl+strings.Join(strings.Fields(note), " ")+nl+nl); err != nil {
+			return err
+		}
+	}
+
+	id := 0
+	for _, seg := range subtitleCues(tr.Segments) {
+		for _, c := range splitCue(seg, cfg.maxCueDuration) {
+			id++
+			var b strings.Builder
+			if cfg.cueIDs {
+				fmt.Fprintf(&b, "%d%s", id, nl)
+			}
+			text := vttEscaper.Replace(c.text)
+			if speaker := strings.TrimSpace(c.speaker); speaker != "" {
+				text = fmt.Sprintf("<v %s>%s</v>", vttEscaper.Replace(speaker), text)
+			}
+			fmt.Fprintf(&b, "%s --> %s%s%s%s%s", vttTimestamp(c.start), vttTimestamp(c.end), nl, text, nl, nl)
+			if _, err := io.WriteString(w, b.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitCue splits c into cues no longer than maxDuration, dividing the text
+// by words. A cue with fewer words than needed parts is split less.
+func splitCue(c cue, maxDuration time.Duration) []cue {
+	if maxDuration <= 0 || c.end-c.start <= maxDuration {
+		return []cue{c}
+	}
+	words := strings.Fields(c.text)
+	n := int((c.end - c.start + maxDuration - 1) / maxDuration)
+	n = min(n, len(words))
+	step := (c.end - c.start) / time.Duration(n)
+	cues := make([]cue, n)
+	for i := range cues {
+		cues[i] = cue{
+			start:   c.start + time.Duration(i)*step,
+			end:     c.start + time.Duration(i+1)*step,
+			text:    strings.Join(words[i*len(words)/n:(i+1)*len(words)/n], " "),
+			speaker: c.speaker,
+		}
+	}
+	cues[n-1].end = c.end
+	return cues
+}
+
+// vttTimestamp formats d as HH:MM:SS.mmm.
+func vttTimestamp(d time.Duration) string {
+	return strings.Replace(srtTimestamp(d), ",", ".", 1)
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// vttCue is a cue parsed by parseVTT.
+type vttCue struct {
+	ID         string
+	Start, End string
+	Text       string
+}
+
+var (
+	vttTimingRe = regexp.MustCompile(`^(\d{2,}:[0-5]\d:[0-5]\d\.\d{3}) --> (\d{2,}:[0-5]\d:[0-5]\d\.\d{3})$`)
+	vttTagRe    = regexp.MustCompile(`<v [^<>&]+>|</v>`)
+	vttEntityRe = regexp.MustCompile(`&(amp|lt|gt);`)
+)
+
+// parseVTT parses a WebVTT document strictly: consistent line endings, a
+// WEBVTT header, blocks separated by single blank lines, well-formed and
+// ordered timings, and cue text using only voice spans and escaped
+// characters.
+func parseVTT(doc string) ([]vttCue, error) {
+	nl := "\n"
+	if strings.Contains(doc, "\r\n") {
+		nl = "\r\n"
+		if strings.Count(doc, "\n") != strings.Count(doc, "\r\n") {
+			return nil, errors.New("mixed line endings")
+		}
+	}
+	if strings.Contains(strings.ReplaceAll(doc, "\r\n", ""), "\r") {
+		return nil, errors.New("bare carriage return")
+	}
+	if !strings.HasSuffix(doc, nl+nl) {
+		return nil, errors.New("document does not end with a blank line")
+	}
+	blocks := strings.Split(strings.TrimSuffix(doc, nl+nl), nl+nl)
+	if blocks[0] != "WEBVTT" {
+		return nil, fmt.Errorf("header is %q, want WEBVTT", blocks[0])
+	}
+
+	var cues []vttCue
+	var prevStart string
+	for _, block := range blocks[1:] {
+		if block == "" {
+			return nil, errors.New("empty block")
+		}
+		lines := strings.Split(block, nl)
+		if lines[0] == "NOTE" || strings.HasPrefix(lines[0], "NOTE ") {
+			if strings.Contains(block, "-->") {
+				return nil, fmt.Errorf("note contains -->: %q", block)
+			}
+			if len(cues) > 0 {
+				return nil, errors.New("note after the first cue")
+			}
+			continue
+		}
+		var c vttCue
+		if !strings.Contains(lines[0], "-->") {
+			c.ID, lines = lines[0], lines[1:]
+		}
+		if len(lines) < 2 {
+			return nil, fmt.Errorf("cue without timing or text: %q", block)
+		}
+		m := vttTimingRe.FindStringSubmatch(lines[0])
+		if m == nil {
+			return nil, fmt.Errorf("invalid timing line %q", lines[0])
+		}
+		c.Start, c.End = m[1], m[2]
+		// Fixed-width timestamps compare correctly as strings.
+		if c.End <= c.Start {
+			return nil, fmt.Errorf("cue ends before it starts: %q", lines[0])
+		}
+		if c.Start < prevStart {
+			return nil, fmt.Errorf("cue starts before the previous one: %q", lines[0])
+		}
+		prevStart = c.Start
+		for _, line := range lines[1:] {
+			if line == "" || strings.Contains(line, "-->") {
+				return nil, fmt.Errorf("invalid cue text line %q", line)
+			}
+			bare := vttEntityRe.ReplaceAllString(vttTagRe.ReplaceAllString(line, ""), "")
+			if strings.ContainsAny(bare, "<>&") {
+				return nil, fmt.Errorf("unescaped markup in %q", line)
+			}
+		}
+		c.Text = strings.Join(lines[1:], "\n")
+		cues = append(cues, c)
+	}
+	return cues, nil
+}
+
+func vttFixture() *TranscribeResponse {
+	return &TranscribeResponse{Segments: []Segment{
+		{Start: 0, End: 2.5, Text: " Hello & welcome <everyone>.", Speaker: "Alice"},
+		{Start: 2.5, End: 2.5, Text: " zero length"},
+		{Start: 3, End: 4, Text: "   "},
+		{Start: 3725.25, End: 3727, Text: " See you -> later."},
+	}}
+}
+
+func TestVTT(t *testing.T) {
+	doc, err := vttFixture().VTT(WithVTTCueIDs(), WithVTTNote("Generated\n\nby --> whisper"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cues, err := parseVTT(doc)
+	if err != nil {
+		t.Fatalf("invalid WebVTT: %v\n%s", err, doc)
+	}
+	want := []vttCue{
+		{ID: "1", Start: "00:00:00.000", End: "00:00:02.500", Text: "<v Alice>Hello &amp; welcome &lt;everyone&gt;.</v>"},
+		{ID: "2", Start: "01:02:05.250", End: "01:02:07.000", Text: "See you -&gt; later."},
+	}
+	if !reflect.DeepEqual(cues, want) {
+		t.Errorf("cues = %+v, want %+v", cues, want)
+	}
+	if !strings.Contains(doc, "\nNOTE\nGenerated by -> whisper\n\n") {
+		t.Errorf("VTT() has no note block:\n%s", doc)
+	}
+}
+
+func TestVTTCRLF(t *testing.T) {
+	doc, err := vttFixture().VTT(WithVTTCRLF(), WithVTTNote("note"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(doc, "\n") != strings.Count(doc, "\r\n") {
+		t.Errorf("VTT() with CRLF has bare line feeds: %q", doc)
+	}
+	cues, err := parseVTT(doc)
+	if err != nil {
+		t.Fatalf("invalid WebVTT: %v\n%q", err, doc)
+	}
+	if len(cues) != 2 || cues[0].ID != "" {
+		t.Errorf("cues = %+v, want two cues without IDs", cues)
+	}
+}
+
+func TestVTTMaxCueDuration(t *testing.T) {
+	tr := &TranscribeResponse{Segments: []Segment{
+		{Start: 0, End: 10, Text: " one two three four five six", Speaker: "Bob"},
+		{Start: 10, End: 11, Text: " short"},
+		{Start: 11, End: 20, Text: " single"},
+	}}
+	doc, err := tr.VTT(WithVTTMaxCueDuration(4 * time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cues, err := parseVTT(doc)
+	if err != nil {
+		t.Fatalf("invalid WebVTT: %v\n%s", err, doc)
+	}
+	want := []vttCue{
+		{Start: "00:00:00.000", End: "00:00:03.333", Text: "<v Bob>one two</v>"},
+		{Start: "00:00:03.333", End: "00:00:06.667", Text: "<v Bob>three four</v>"},
+		{Start: "00:00:06.667", End: "00:00:10.000", Text: "<v Bob>five six</v>"},
+		{Start: "00:00:10.000", End: "00:00:11.000", Text: "short"},
+		{Start: "00:00:11.000", End: "00:00:20.000", Text: "single"},
+	}
+	if !reflect.DeepEqual(cues, want) {
+		t.Errorf("cues = %+v, want %+v", cues, want)
+	}
+}
+
+func TestVTTNoSegments(t *testing.T) {
+	if _, err := (&TranscribeResponse{Text: "text only"}).VTT(); !errors.Is(err, ErrNoSegments) {
+		t.Errorf("VTT() without segments = %v, want ErrNoSegments", err)
+	}
+}
+
+func TestParseVTTRejects(t *testing.T) {
+	for _, doc := range []string{
+		"WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nno blank line at the end\n",
+		"WEBVTT\n\n00:00:02.000 --> 00:00:01.000\nbackwards\n\n",
+		"WEBVTT\n\n00:00:01,000 --> 00:00:02,000\nSRT timing\n\n",
+		"WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nunescaped <b>tag</b>\n\n",
+		"WEBVTT\r\n\r\n00:00:01.000 --> 00:00:02.000\nmixed\r\n\r\n",
+		"WEBVTT\n\n00:00:03.000 --> 00:00:04.000\nlater\n\n00:00:01.000 --> 00:00:02.000\nearlier\n\n",
+	} {
+		if _, err := parseVTT(doc); err == nil {
+			t.Errorf("parseVTT(%q) succeeded", doc)
+		}
+	}
+}
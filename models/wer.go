@@ -0,0 +1,231 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EditOp is the kind of an aligned word in a WERResult.
+type EditOp int
+
+// The kinds of aligned words.
+const (
+	OpMatch EditOp = iota
+	OpSubstitution
+	OpDeletion
+	OpInsertion
+)
+
+func (op EditOp) String() string {
+	switch op {
+	case OpMatch:
+		return "match"
+	case OpSubstitution:
+		return "substitution"
+	case OpDeletion:
+		return "deletion"
+	case OpInsertion:
+		return "insertion"
+	}
+	return "EditOp(" + strconv.Itoa(int(op)) + ")"
+}
+
+// WordEdit is a step of the alignment of a hypothesis to a reference. Ref is
+// empty for insertions and Hyp for deletions.
+type WordEdit struct {
+	Op  EditOp
+	Ref string
+	Hyp string
+}
+
+// WERResult is the word error rate of a hypothesis against a reference.
+type WERResult struct {
+	// WER is (Substitutions + Deletions + Insertions) / ReferenceWords. It
+	// is 0 if both texts are empty and 1 if only the reference is.
+	WER           float64
+	Substitutions int
+	Deletions     int
+	Insertions    int
+	// ReferenceWords is the number of words in the normalized reference.
+	ReferenceWords int
+	// Edits is the alignment of the normalized words, in order, e.g. to
+	// render a diff.
+	Edits []WordEdit
+}
+
+// WERNormalization selects how texts are normalized before they are
+// compared.
+type WERNormalization struct {
+	// Lowercase folds the texts to lower case.
+	Lowercase bool
+	// StripPunctuation removes punctuation and symbols around words.
+	StripPunctuation bool
+	// Numbers spells English number words from zero to ninety-nine as
+	// digits and removes thousands separators, so that "twenty five" and
+	// "25" match.
+	Numbers bool
+}
+
+// DefaultWERNormalization lowercases and strips punctuation.
+var DefaultWERNormalization = WERNormalization{Lowercase: true, StripPunctuation: true}
+
+// werConfig holds the settings of WER.
+type werConfig struct {
+	norm WERNormalization
+}
+
+// WEROption configures WER.
+type WEROption func(*werConfig)
+
+// WithNormalization sets the normalization applied before comparing. It
+// defaults to DefaultWERNormalization.
+func WithNormalization(n WERNormalization) WEROption {
+	return func(c *werConfig) {
+		c.norm = n
+	}
+}
+
+// WER computes the word error rate of hypothesis against reference from the
+// minimal alignment of their words by substitutions, deletions and
+// insertions.
+func WER(reference, hypothesis string, opts ...WEROption) WERResult {
+	cfg := werConfig{norm: DefaultWERNormalization}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	ref := cfg.norm.words(reference)
+	hyp := cfg.norm.words(hypothesis)
+
+	// d[i][j] is the edit distance between ref[:i] and hyp[:j].
+	d := make([][]int, len(ref)+1)
+	for i := range d {
+		d[i] = make([]int, len(hyp)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ref); i++ {
+		for j := 1; j <= len(hyp); j++ {
+			sub := d[i-1][j-1]
+			if ref[i-1] != hyp[j-1] {
+				sub++
+			}
+			d[i][j] = min(sub, d[i-1][j]+1, d[i][j-1]+1)
+		}
+	}
+
+	res := WERResult{ReferenceWords: len(ref)}
+	for i, j := len(ref), len(hyp); i > 0 || j > 0; {
+		switch {
+		case i > 0 && j > 0 && ref[i-1] == hyp[j-1] && d[i][j] == d[i-1][j-1]:
+			res.Edits = append(res.Edits, WordEdit{Op: OpMatch, Ref: ref[i-1], Hyp: hyp[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && d[i][j] == d[i-1][j-1]+1:
+			res.Edits = append(res.Edits, WordEdit{Op: OpSubstitution, Ref: ref[i-1], Hyp: hyp[j-1]})
+			res.Substitutions++
+			i, j = i-1, j-1
+		case i > 0 && d[i][j] == d[i-1][j]+1:
+			res.Edits = append(res.Edits, WordEdit{Op: OpDeletion, Ref: ref[i-1]})
+			res.Deletions++
+			i--
+		default:
+			res.Edits = append(res.Edits, WordEdit{Op: OpInsertion, Hyp: hyp[j-1]})
+			res.Insertions++
+			j--
+		}
+	}
+	for l, r := 0, len(res.Edits)-1; l < r; l, r = l+1, r-1 {
+		res.Edits[l], res.Edits[r] = res.Edits[r], res.Edits[l]
+	}
+
+	errs := res.Substitutions + res.Deletions + res.Insertions
+	switch {
+	case len(ref) > 0:
+		res.WER = float64(errs) / float64(len(ref))
+	case errs > 0:
+		res.WER = 1
+	}
+	return res
+}
+
+// DiffAgainst computes the word error rate of Text against a reference
+// transcript.
+func (tr *TranscribeResponse) DiffAgainst(referenceText string, opts ...WEROption) WERResult {
+	return WER(referenceText, tr.Text, opts...)
+}
+
+var (
+	numberUnits = map[string]int{
+		"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+		"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16,
+		"seventeen": 17, "eighteen": 18, "nineteen": 19,
+	}
+	numberTens = map[string]int{
+		"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50, "sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+	}
+)
+
+// words splits s into normalized words.
+func (n WERNormalization) words(s string) []string {
+	if n.Lowercase {
+		s = strings.ToLower(s)
+	}
+	if n.Numbers {
+		// Hyphenated numbers like twenty-five are split into their words.
+		s = strings.ReplaceAll(s, "-", " ")
+	}
+	var words []string
+	for _, w := range strings.Fields(s) {
+		if n.StripPunctuation {
+			w = strings.TrimFunc(w, func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) })
+		}
+		if n.Numbers {
+			if digits := strings.ReplaceAll(w, ",", ""); digits != w && isDigits(digits) {
+				w = digits
+			}
+		}
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+	if n.Numbers {
+		words = spellNumbers(words)
+	}
+	return words
+}
+
+// spellNumbers replaces English number words below 100 with digits.
+func spellNumbers(words []string) []string {
+	out := words[:0]
+	for i := 0; i < len(words); i++ {
+		w := strings.ToLower(words[i])
+		if tens, ok := numberTens[w]; ok {
+			if i+1 < len(words) {
+				if unit, ok := numberUnits[strings.ToLower(words[i+1])]; ok && unit > 0 && unit < 10 {
+					out = append(out, strconv.Itoa(tens+unit))
+					i++
+					continue
+				}
+			}
+			out = append(out, strconv.Itoa(tens))
+			continue
+		}
+		if unit, ok := numberUnits[w]; ok {
+			out = append(out, strconv.Itoa(unit))
+			continue
+		}
+		out = append(out, words[i])
+	}
+	return out
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
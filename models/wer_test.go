@@ -0,0 +1,81 @@
+package models
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestWER(t *testing.T) {
+	tests := []struct {
+		name          string
+		ref, hyp      string
+		norm          WERNormalization
+		wer           float64
+		sub, del, ins int
+	}{
+		{"identical", "the cat sat", "the cat sat", DefaultWERNormalization, 0, 0, 0, 0},
+		{"substitution", "the cat sat", "the bat sat", DefaultWERNormalization, 1.0 / 3, 1, 0, 0},
+		{"deletion", "the cat sat down", "the cat down", DefaultWERNormalization, 0.25, 0, 1, 0},
+		{"insertion", "the cat sat", "the cat sat down", DefaultWERNormalization, 1.0 / 3, 0, 0, 1},
+		{"mixed", "one two three four five six", "one too three five six seven", DefaultWERNormalization, 0.5, 1, 1, 1},
+		{"normalized", "Hello, World!", "hello world", DefaultWERNormalization, 0, 0, 0, 0},
+		{"not normalized", "Hello, World!", "hello world", WERNormalization{}, 1, 2, 0, 0},
+		{"numbers", "I have twenty-five cats and 1,000 dogs", "i have 25 cats and 1000 dogs",
+			WERNormalization{Lowercase: true, StripPunctuation: true, Numbers: true}, 0, 0, 0, 0},
+		{"numbers off", "twenty five", "25", DefaultWERNormalization, 1, 1, 1, 0},
+		{"empty", "", "", DefaultWERNormalization, 0, 0, 0, 0},
+		{"empty reference", "", "hello", DefaultWERNormalization, 1, 0, 0, 1},
+		{"empty hypothesis", "hello world", "", DefaultWERNormalization, 1, 0, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WER(tt.ref, tt.hyp, WithNormalization(tt.norm))
+			if math.Abs(got.WER-tt.wer) > 1e-9 {
+				t.Errorf("WER = %v, want %v", got.WER, tt.wer)
+			}
+			if got.Substitutions != tt.sub || got.Deletions != tt.del || got.Insertions != tt.ins {
+				t.Errorf("S/D/I = %d/%d/%d, want %d/%d/%d",
+					got.Substitutions, got.Deletions, got.Insertions, tt.sub, tt.del, tt.ins)
+			}
+		})
+	}
+}
+
+func TestWERAlignment(t *testing.T) {
+	got := WER("the cat sat on the mat", "well the cat sit on mat")
+	want := []WordEdit{
+		{Op: OpInsertion, Hyp: "well"},
+		{Op: OpMatch, Ref: "the", Hyp: "the"},
+		{Op: OpMatch, Ref: "cat", Hyp: "cat"},
+		{Op: OpSubstitution, Ref: "sat", Hyp: "sit"},
+		{Op: OpMatch, Ref: "on", Hyp: "on"},
+		{Op: OpDeletion, Ref: "the"},
+		{Op: OpMatch, Ref: "mat", Hyp: "mat"},
+	}
+	if !reflect.DeepEqual(got.Edits, want) {
+		t.Errorf("Edits = %+v, want %+v", got.Edits, want)
+	}
+	if got.ReferenceWords != 6 || got.WER != 0.5 {
+		t.Errorf("ReferenceWords, WER = %d, %v, want 6, 0.5", got.ReferenceWords, got.WER)
+	}
+}
+
+func TestDiffAgainst(t *testing.T) {
+	tr := &TranscribeResponse{Text: " The quick brown fox."}
+	got := tr.DiffAgainst("the quick brown dog")
+	if got.WER != 0.25 || got.Substitutions != 1 {
+		t.Errorf("DiffAgainst() = %+v, want one substitution and a WER of 0.25", got)
+	}
+	if exact := tr.DiffAgainst("the quick brown fox", WithNormalization(WERNormalization{})); exact.WER != 0.5 {
+		t.Errorf("DiffAgainst() without normalization WER = %v, want 0.5", exact.WER)
+	}
+}
+
+func TestEditOpString(t *testing.T) {
+	for op, want := range map[EditOp]string{OpMatch: "match", OpSubstitution: "substitution", OpDeletion: "deletion", OpInsertion: "insertion", 7: "EditOp(7)"} {
+		if got := op.String(); got != want {
+			t.Errorf("EditOp(%d).String() = %q, want %q", int(op), got, want)
+		}
+	}
+}
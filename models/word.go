@@ -0,0 +1,28 @@
+package models
+
+import "sort"
+
+// Word is a single word with its timestamps, returned when word-level
+// timestamp granularity is requested.
+type Word struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	// Probability is the confidence of the word, if the server reports it.
+	Probability float64 `json:"probability,omitempty"`
+}
+
+// AllWords returns the words of the response in time order, whether the
+// server returned them at the top level, as the OpenAI API does, or nested
+// in the segments, as some compatible servers do.
+func (tr *TranscribeResponse) AllWords() []Word {
+	if len(tr.Words) > 0 {
+		return append([]Word(nil), tr.Words...)
+	}
+	var words []Word
+	for _, seg := range tr.Segments {
+		words = append(words, seg.Words...)
+	}
+	sort.SliceStable(words, func(i, j int) bool { return words[i].Start < words[j].Start })
+	return words
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func loadFixture(t *testing.T, name string) *TranscribeResponse {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tr TranscribeResponse
+	if err := json.Unmarshal(b, &tr); err != nil {
+		t.Fatal(err)
+	}
+	return &tr
+}
+
+func TestAllWords(t *testing.T) {
+	want := []Word{
+		{Word: "Hello", Start: 0, End: 0.42},
+		{Word: "there,", Start: 0.42, End: 1.1},
+		{Word: "world.", Start: 1.3, End: 2.2},
+	}
+	for _, name := range []string{"words_top_level.json", "words_nested.json"} {
+		t.Run(name, func(t *testing.T) {
+			words := loadFixture(t, name).AllWords()
+			if len(words) != len(want) {
+				t.Fatalf("AllWords() = %+v, want %d words", words, len(want))
+			}
+			for i, w := range words {
+				if w.Word != want[i].Word || w.Start != want[i].Start || w.End != want[i].End {
+					t.Errorf("word %d = %+v, want %+v", i, w, want[i])
+				}
+			}
+		})
+	}
+
+	nested := loadFixture(t, "words_nested.json")
+	if p := nested.AllWords()[2].Probability; p != 0.87 {
+		t.Errorf("Probability = %v, want 0.87", p)
+	}
+}
+
+func TestAllWordsCopies(t *testing.T) {
+	for _, name := range []string{"words_top_level.json", "words_nested.json"} {
+		tr := loadFixture(t, name)
+		tr.AllWords()[0].Word = "changed"
+		if tr.AllWords()[0].Word != "Hello" {
+			t.Errorf("%s: modifying the result of AllWords changed the response", name)
+		}
+	}
+}
+
+func TestAllWordsOrder(t *testing.T) {
+	// Segments of some servers overlap, so their words must be merged by time.
+	tr := &TranscribeResponse{Segments: []Segment{
+		{Start: 0, End: 2, Words: []Word{{Word: "a", Start: 0}, {Word: "c", Start: 1.5}}},
+		{Start: 1, End: 3, Words: []Word{{Word: "b", Start: 1}, {Word: "d", Start: 2.5}}},
+	}}
+	var got string
+	for _, w := range tr.AllWords() {
+		got += w.Word
+	}
+	if got != "abcd" {
+		t.Errorf("AllWords() in order %q, want %q", got, "abcd")
+	}
+	if words := (&TranscribeResponse{Segments: []Segment{seg(0, 1, " no words")}}).AllWords(); len(words) != 0 {
+		t.Errorf("AllWords() without word timestamps = %+v, want none", words)
+	}
+}
@@ -0,0 +1,47 @@
+package transcribe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidResponseFormat is returned by the Transcribe call when
+// WithResponseFormatTyped is given an unknown format.
+var ErrInvalidResponseFormat = errors.New("invalid response format")
+
+// ResponseFormat is a response_format supported by the OpenAI API.
+type ResponseFormat string
+
+// The response formats supported by the OpenAI API.
+const (
+	FormatJSON        ResponseFormat = "json"
+	FormatText        ResponseFormat = "text"
+	FormatSRT         ResponseFormat = "srt"
+	FormatVerboseJSON ResponseFormat = "verbose_json"
+	FormatVTT         ResponseFormat = "vtt"
+)
+
+// Valid reports whether f is one of the known formats.
+func (f ResponseFormat) Valid() bool {
+	switch f {
+	case FormatJSON, FormatText, FormatSRT, FormatVerboseJSON, FormatVTT:
+		return true
+	}
+	return false
+}
+
+// WithResponseFormatTyped is like WithResponseFormat for the known formats.
+// An unknown format is reported by the Transcribe call as
+// ErrInvalidResponseFormat; use WithResponseFormat for formats of custom
+// backends.
+func WithResponseFormatTyped(format ResponseFormat) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		if !format.Valid() {
+			if tc.err == nil {
+				tc.err = fmt.Errorf("%w: %q", ErrInvalidResponseFormat, format)
+			}
+			return
+		}
+		tc.ResponseFormat = string(format)
+	}
+}
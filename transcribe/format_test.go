@@ -0,0 +1,50 @@
+package transcribe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResponseFormatValid(t *testing.T) {
+	tests := []struct {
+		format ResponseFormat
+		valid  bool
+	}{
+		{FormatJSON, true},
+		{FormatText, true},
+		{FormatSRT, true},
+		{FormatVerboseJSON, true},
+		{FormatVTT, true},
+		{"", false},
+		{"JSON", false},
+		{"verbose-json", false},
+		{"diarized_json", false},
+	}
+	for _, tt := range tests {
+		if got := tt.format.Valid(); got != tt.valid {
+			t.Errorf("ResponseFormat(%q).Valid() = %v, want %v", tt.format, got, tt.valid)
+		}
+	}
+}
+
+func TestWithResponseFormatTyped(t *testing.T) {
+	var tc TranscribeConfig
+	WithResponseFormatTyped(FormatSRT)(&tc)
+	if tc.ResponseFormat != "srt" || tc.Err() != nil {
+		t.Errorf("ResponseFormat, Err() = %q, %v; want srt, nil", tc.ResponseFormat, tc.Err())
+	}
+
+	WithResponseFormatTyped("yaml")(&tc)
+	if !errors.Is(tc.Err(), ErrInvalidResponseFormat) {
+		t.Errorf("Err() = %v, want ErrInvalidResponseFormat", tc.Err())
+	}
+	if tc.ResponseFormat != "srt" {
+		t.Errorf("ResponseFormat = %q, want srt kept", tc.ResponseFormat)
+	}
+
+	// The first error is kept.
+	WithResponseFormatTyped("xml")(&tc)
+	if err := tc.Err(); err == nil || err.Error() != `invalid response format: "yaml"` {
+		t.Errorf("Err() = %v, want the error for yaml", err)
+	}
+}
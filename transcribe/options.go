@@ -1,10 +1,76 @@
 package transcribe
 
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/akhilsharma90/go-whisper-project/models"
+)
+
 // TranscribeConfig is a structure that holds the configuration for the Transcribe method.
 type TranscribeConfig struct {
 	Model    string
 	Language string
 	File     string
+	APIKey   string
+	Prompt   string
+
+	// ResponseFormat is the response_format requested from the API, e.g.
+	// "json", "verbose_json", "text", "srt" or "vtt".
+	ResponseFormat string
+
+	// RequestID is sent as the X-Request-ID header. One is generated when empty.
+	RequestID string
+
+	// IdempotencyKey is sent as the Idempotency-Key header. One is generated
+	// when empty and the client retries requests.
+	IdempotencyKey string
+
+	// TimestampOffset is added to the segment and word timestamps of the response.
+	TimestampOffset time.Duration
+
+	// NormalizeText normalizes whitespace in the returned text, and unicode
+	// if NormalizeOptions ask for it.
+	NormalizeText    bool
+	NormalizeOptions []models.NormalizeOption
+
+	// Replacements are whole-word replacements applied to the returned
+	// segment texts, matched case-insensitively.
+	Replacements map[string]string
+
+	// NoRetry disables the retries configured on the client for this call.
+	NoRetry bool
+
+	// OnRetry, if set, is called before a failed attempt is retried with the
+	// 1-based number of that attempt, its error, and the wait before the retry.
+	OnRetry func(attempt int, err error, wait time.Duration)
+
+	// TimestampGranularities are the timestamp_granularities[] requested,
+	// "segment" and/or "word". They require the verbose_json format.
+	TimestampGranularities []string
+
+	// Include are the include[] values requested, e.g. "logprobs".
+	Include []string
+
+	// AllowEmptyResult accepts a response without any text or segments.
+	AllowEmptyResult bool
+
+	// ExtraFields are additional form fields sent with the request, as
+	// name/value pairs in order.
+	ExtraFields [][2]string
+
+	// PromptTokenLimit, when positive, truncates Prompt at a word boundary
+	// to roughly this many tokens.
+	PromptTokenLimit int
+
+	err error
+}
+
+// Err returns the first error encountered while applying options.
+func (tc *TranscribeConfig) Err() error {
+	return tc.err
 }
 
 // TranscribeOption is a function type that allows to set options for the Transcribe method.
@@ -31,4 +97,163 @@ func WithFile(file string) TranscribeOption {
 	}
 }
 
+// WithAPIKey overrides the client's API key for a single Transcribe call.
+func WithAPIKey(key string) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.APIKey = key
+	}
+}
+
+// WithPrompt sets the prompt used to guide the model's style or vocabulary.
+func WithPrompt(prompt string) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.Prompt = prompt
+	}
+}
+
+// WithPromptFile sets the prompt to the contents of the file at path. A read
+// error is reported by the Transcribe call.
+func WithPromptFile(path string) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			if tc.err == nil {
+				tc.err = fmt.Errorf("reading prompt file: %w", err)
+			}
+			return
+		}
+		tc.Prompt = string(b)
+	}
+}
+
+// WithPromptTokenLimit truncates the prompt to roughly the given number of
+// tokens, keeping its end and cutting at a word boundary. Whisper only
+// considers the last 224 prompt tokens.
+func WithPromptTokenLimit(tokens int) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.PromptTokenLimit = tokens
+	}
+}
+
+// WithResponseFormat sets the response_format requested from the API. Formats
+// other than "json" and "verbose_json" are returned verbatim in the Text field.
+func WithResponseFormat(format string) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.ResponseFormat = format
+	}
+}
+
+// WithResponseFormatJSON requests the lightweight "json" format, which only
+// carries the transcribed text.
+func WithResponseFormatJSON() TranscribeOption {
+	return WithResponseFormat(string(FormatJSON))
+}
+
+// WithVerbose requests the "verbose_json" format, which includes the detected
+// language, duration and segments.
+func WithVerbose() TranscribeOption {
+	return WithResponseFormat(string(FormatVerboseJSON))
+}
+
+// WithRequestID sets the X-Request-ID sent with the request, so that an
+// upstream correlation ID can be propagated.
+func WithRequestID(id string) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.RequestID = id
+	}
+}
+
+// WithTextNormalization trims and collapses whitespace in the returned text.
+// Pass models.WithUnicodeNormalization to also normalize unicode, e.g. to NFC.
+func WithTextNormalization(opts ...models.NormalizeOption) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.NormalizeText = true
+		tc.NormalizeOptions = opts
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header, so that a retried
+// request is not processed and billed twice.
+func WithIdempotencyKey(key string) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.IdempotencyKey = key
+	}
+}
+
+// WithTimestampOffset shifts the segment and word timestamps of the response
+// by d, e.g. the position of a chunk within a longer recording. The reported
+// Duration is not changed.
+func WithTimestampOffset(d time.Duration) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.TimestampOffset = d
+	}
+}
+
+// WithExtraFormField sends an additional form field with the request, for
+// parameters of OpenAI-compatible servers the library does not know about,
+// e.g. hotwords. It may be given several times.
+func WithExtraFormField(key, value string) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.ExtraFields = append(tc.ExtraFields, [2]string{key, value})
+	}
+}
+
+// WithNoRetry sends the request only once, even if the client retries
+// failed requests by default.
+func WithNoRetry() TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.NoRetry = true
+	}
+}
+
+// WithOnRetry calls fn before each retry, e.g. for logging.
+func WithOnRetry(fn func(attempt int, err error, wait time.Duration)) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.OnRetry = fn
+	}
+}
 
+// WithTimestampGranularities requests timestamps at the given granularities,
+// "segment" and/or "word". It requires the verbose_json response format.
+func WithTimestampGranularities(granularities ...string) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.TimestampGranularities = append(tc.TimestampGranularities, granularities...)
+	}
+}
+
+// WithLogprobs requests the log probabilities of the tokens, which only the
+// gpt-4o transcription models support.
+func WithLogprobs() TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.Include = append(tc.Include, "logprobs")
+	}
+}
+
+// WithAllowEmptyResult accepts an empty transcript, e.g. for audio that may
+// be silent, instead of failing with an empty response error.
+func WithAllowEmptyResult() TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		tc.AllowEmptyResult = true
+	}
+}
+
+// WithReplacements applies the replacements to the segment texts of the
+// response and rebuilds its Text, e.g. to correct "gonna" to "going to".
+// Keys are matched case-insensitively and only as whole words. It may be
+// given several times. Keys that differ only in case are reported as
+// models.ErrReplacementConflict by the Transcribe call.
+func WithReplacements(replacements map[string]string) TranscribeOption {
+	return func(tc *TranscribeConfig) {
+		if tc.Replacements == nil {
+			tc.Replacements = make(map[string]string, len(replacements))
+		}
+		for k, v := range replacements {
+			for existing := range tc.Replacements {
+				if existing != k && strings.EqualFold(existing, k) && tc.err == nil {
+					tc.err = fmt.Errorf("%w: %q and %q", models.ErrReplacementConflict, existing, k)
+				}
+			}
+			tc.Replacements[k] = v
+		}
+	}
+}